@@ -0,0 +1,213 @@
+package memeduck
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+
+	"cloud.google.com/go/spanner"
+	"github.com/pkg/errors"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+// BatchLimits specifies limits on each statement made by InsertStmt.Batch.
+// Zero values mean no limits.
+type BatchLimits struct {
+	// MaxRows is the maximum number of rows in a statement.
+	MaxRows int
+	// MaxBytes is the maximum size of a statement in bytes,
+	// which is the length of its SQL text plus the estimated size of its query parameters.
+	MaxBytes int
+	// MaxMutations is the maximum number of mutations in a statement, which is the number of columns times the number of rows.
+	MaxMutations int
+}
+
+// Batch splits the INSERT statement into multiple statements so that each of them satisfies limits.
+// The statements can be executed at once by (*spanner.ReadWriteTransaction).BatchUpdate.
+//
+// Rows are kept in the original order and each statement has as many rows as possible.
+// Only INSERT statements with VALUES clause can be split.
+func (s *InsertStmt) Batch(limits BatchLimits) ([]spanner.Statement, error) {
+	if s.query != nil {
+		return nil, errors.New("INSERT ... SELECT can't be split into batches")
+	}
+	if s.values == nil {
+		return nil, errors.New("no VALUES specified")
+	}
+	rowsV := reflect.ValueOf(s.values)
+	if rowsV.Kind() != reflect.Slice {
+		return nil, errors.Errorf("%s is not a slice", rowsV.Type().String())
+	}
+	if rowsV.Len() <= 0 {
+		return nil, errors.New("empty values")
+	}
+	t, err := s.withColumns()
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBatcher(t, limits)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]interface{}, 0, rowsV.Len())
+	for i := 0; i < rowsV.Len(); i++ {
+		rows = append(rows, rowsV.Index(i).Interface())
+	}
+	var stmts []spanner.Statement
+	for len(rows) > 0 {
+		stmt, n, err := b.split(rows)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+		rows = rows[n:]
+	}
+	if err := b.checkUnused(); err != nil {
+		return nil, err
+	}
+	return stmts, nil
+}
+
+// batcher makes INSERT statements that satisfy limits from rows.
+type batcher struct {
+	stmt    *InsertStmt
+	limits  BatchLimits
+	maxRows int
+	// used holds query parameters that are used in statements made so far.
+	used map[string]bool
+}
+
+// newBatcher creates a new batcher.
+// The column names of stmt must be resolved beforehand.
+func newBatcher(stmt *InsertStmt, limits BatchLimits) (*batcher, error) {
+	if limits.MaxRows < 0 || limits.MaxBytes < 0 || limits.MaxMutations < 0 {
+		return nil, errors.Errorf("negative batch limits: %+v", limits)
+	}
+	if len(stmt.cols) <= 0 {
+		return nil, errors.New("no columns specified")
+	}
+	maxRows := limits.MaxRows
+	if limits.MaxMutations > 0 {
+		n := limits.MaxMutations / len(stmt.cols)
+		if n <= 0 {
+			return nil, errors.Errorf("a row of %d columns exceeds MaxMutations (%d)", len(stmt.cols), limits.MaxMutations)
+		}
+		if maxRows <= 0 || n < maxRows {
+			maxRows = n
+		}
+	}
+	return &batcher{
+		stmt:    stmt,
+		limits:  limits,
+		maxRows: maxRows,
+		used:    map[string]bool{},
+	}, nil
+}
+
+// split returns a statement that consists of the longest prefix of rows that satisfies limits,
+// along with the number of rows in it.
+func (b *batcher) split(rows []interface{}) (spanner.Statement, int, error) {
+	n := len(rows)
+	if b.maxRows > 0 && n > b.maxRows {
+		n = b.maxRows
+	}
+	stmt, err := b.statement(rows[:n])
+	if err != nil {
+		return spanner.Statement{}, 0, err
+	}
+	if !b.fits(stmt) {
+		// The size of statements increases monotonically with the number of rows,
+		// so the longest prefix can be found by binary search; rows[:lo] fits and rows[:hi] doesn't.
+		lo, hi := 0, n
+		for hi-lo > 1 {
+			mid := (lo + hi) / 2
+			midStmt, err := b.statement(rows[:mid])
+			if err != nil {
+				return spanner.Statement{}, 0, err
+			}
+			if b.fits(midStmt) {
+				lo, stmt = mid, midStmt
+			} else {
+				hi = mid
+			}
+		}
+		if lo <= 0 {
+			return spanner.Statement{}, 0, errors.Errorf("a row exceeds MaxBytes (%d)", b.limits.MaxBytes)
+		}
+		n = lo
+	}
+	for name := range stmt.Params {
+		b.used[name] = true
+	}
+	return stmt, n, nil
+}
+
+func (b *batcher) fits(stmt spanner.Statement) bool {
+	return b.limits.MaxBytes <= 0 || statementSize(stmt) <= b.limits.MaxBytes
+}
+
+// statementSize returns the length of SQL text of stmt plus the estimated size of its query parameters.
+func statementSize(stmt spanner.Statement) int {
+	size := len(stmt.SQL)
+	for name, value := range stmt.Params {
+		size += len(name) + paramSize(value)
+	}
+	return size
+}
+
+// paramSize estimates the size of a value of a query parameter when it is sent to Spanner.
+func paramSize(value interface{}) int {
+	switch v := value.(type) {
+	case string:
+		return len(v)
+	case []byte:
+		return base64.StdEncoding.EncodedLen(len(v))
+	}
+	valV := reflect.ValueOf(value)
+	if valV.Kind() == reflect.Slice {
+		size := 0
+		for i := 0; i < valV.Len(); i++ {
+			size += paramSize(valV.Index(i).Interface())
+		}
+		return size
+	}
+	if expr, err := internal.ToExpr(value); err == nil {
+		return len(expr.SQL())
+	}
+	return len(fmt.Sprint(value))
+}
+
+// statement makes an INSERT statement of rows.
+// Parameters bound to the original statement are given only if they are used in rows.
+func (b *batcher) statement(rows []interface{}) (spanner.Statement, error) {
+	var t = *b.stmt
+	t.values = rows
+	stmt, err := t.toDML()
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	used := internal.ParamNames(stmt)
+	var bound []binding
+	for _, p := range t.boundParams() {
+		if used[p.name] {
+			bound = append(bound, p)
+		}
+	}
+	return toStatement(stmt, t.untyped, bound)
+}
+
+// checkUnused returns an error if any parameter bound to the original statement is not used by any statement made so far.
+func (b *batcher) checkUnused() error {
+	return checkUnusedParams(b.stmt, b.used)
+}
+
+// checkUnusedParams returns an error if any parameter bound to stmt is not in used.
+func checkUnusedParams(stmt *InsertStmt, used map[string]bool) error {
+	for _, p := range stmt.boundParams() {
+		if !used[p.name] {
+			return errors.Errorf("query parameter @%s is bound but not used", p.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package memeduck_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck"
+)
+
+func testBatch(t *testing.T, stmt *memeduck.InsertStmt, limits memeduck.BatchLimits, expected []string) {
+	stmts, err := stmt.Batch(limits)
+	if !assert.Nil(t, err, "%+v", limits) {
+		return
+	}
+	actual := make([]string, 0, len(stmts))
+	for _, s := range stmts {
+		actual = append(actual, s.SQL)
+	}
+	assert.Equal(t, expected, actual, "%+v", limits)
+}
+
+func TestInsertBatch(t *testing.T) {
+	stmt := memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}, {3, 4}, {5, 6}, {7, 8}, {9, 10}})
+	testBatch(t, stmt, memeduck.BatchLimits{}, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4), (@p5, @p6), (@p7, @p8), (@p9, @p10)`,
+	})
+	testBatch(t, stmt, memeduck.BatchLimits{MaxRows: 2}, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+	})
+	testBatch(t, stmt, memeduck.BatchLimits{MaxMutations: 7}, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4), (@p5, @p6)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+	})
+	testBatch(t, stmt, memeduck.BatchLimits{MaxRows: 2, MaxMutations: 100}, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+	})
+	testBatch(t, stmt, memeduck.BatchLimits{MaxBytes: 70}, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+	})
+	testBatch(t, stmt, memeduck.BatchLimits{MaxBytes: 48}, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+	})
+}
+
+func TestInsertBatchWithLargeValues(t *testing.T) {
+	large := strings.Repeat("a", 1<<20)
+	stmts, err := memeduck.Insert("hoge", []string{"a"}).
+		Values([][]string{{large + "1"}, {large + "2"}, {large + "3"}}).
+		Batch(memeduck.BatchLimits{MaxBytes: 3 << 19})
+	assert.Nil(t, err)
+	if assert.Len(t, stmts, 3) {
+		for i, stmt := range stmts {
+			assert.Equal(t, `INSERT INTO hoge (a) VALUES (@p1)`, stmt.SQL)
+			assert.Equal(t, map[string]interface{}{"p1": large + strconv.Itoa(i+1)}, stmt.Params)
+		}
+	}
+	_, err = memeduck.Insert("hoge", []string{"a"}).
+		Values([][][]byte{{[]byte(large)}}).
+		Batch(memeduck.BatchLimits{MaxBytes: 1 << 20})
+	assert.Error(t, err, "base64-encoded bytes exceed MaxBytes")
+}
+
+func TestInsertBatchParams(t *testing.T) {
+	stmts, err := memeduck.InsertStruct("hoge", []testInsertGoStructWithOptions{
+		{ID: 1, Name: "foo"},
+		{ID: 2},
+		{ID: 3, Name: "bar"},
+	}).OrUpdate().Batch(memeduck.BatchLimits{MaxRows: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, []spanner.Statement{
+		{
+			SQL:    `INSERT OR UPDATE INTO hoge (id, name, updated_at) VALUES (@p1, @p2, PENDING_COMMIT_TIMESTAMP()), (@p3, DEFAULT, PENDING_COMMIT_TIMESTAMP())`,
+			Params: map[string]interface{}{"p1": int64(1), "p2": "foo", "p3": int64(2)},
+		},
+		{
+			SQL:    `INSERT OR UPDATE INTO hoge (id, name, updated_at) VALUES (@p1, @p2, PENDING_COMMIT_TIMESTAMP())`,
+			Params: map[string]interface{}{"p1": int64(3), "p2": "bar"},
+		},
+	}, stmts)
+
+	stmts, err = memeduck.Insert("hoge", []string{"a"}).
+		Values([][]interface{}{{memeduck.Param("x")}, {1}}).
+		Params(map[string]interface{}{"x": "y"}).
+		Batch(memeduck.BatchLimits{MaxRows: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []spanner.Statement{
+		{SQL: `INSERT INTO hoge (a) VALUES (@x)`, Params: map[string]interface{}{"x": "y"}},
+		{SQL: `INSERT INTO hoge (a) VALUES (@p1)`, Params: map[string]interface{}{"p1": int64(1)}},
+	}, stmts)
+}
+
+func TestInsertBatchWithInvalidArgs(t *testing.T) {
+	stmt := memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}, {3, 4}})
+	for _, limits := range []memeduck.BatchLimits{
+		{MaxRows: -1},
+		{MaxMutations: 1},
+		{MaxBytes: 10},
+	} {
+		_, err := stmt.Batch(limits)
+		assert.Error(t, err, "%+v", limits)
+	}
+	for _, stmt := range []*memeduck.InsertStmt{
+		memeduck.Insert("hoge", []string{"a"}),
+		memeduck.Insert("hoge", []string{"a"}).Values([][]int{}),
+		memeduck.Insert("hoge", []string{"a"}).Values(map[string]int{"a": 1}),
+		memeduck.Insert("hoge", []string{"a"}).Select(memeduck.Select("fuga", []string{"a"})),
+		memeduck.Insert("hoge", []string{"a"}).Values([][]int{{1}}).Params(map[string]interface{}{"x": 1}),
+		memeduck.InsertStruct("hoge", [][]int{{1}}),
+	} {
+		_, err := stmt.Batch(memeduck.BatchLimits{MaxRows: 1})
+		assert.Error(t, err)
+	}
+}
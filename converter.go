@@ -0,0 +1,19 @@
+package memeduck
+
+import (
+	"reflect"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+// ValueConverter converts a Go value into another Go value that memeduck can convert into a SQL expression.
+type ValueConverter = internal.ValueConverter
+
+// RegisterConverter registers conv as a converter for values of type typ.
+// This is useful to use third-party types (e.g. UUIDs or decimals) as SQL expressions without wrapping them.
+//
+// If typ is an interface type, conv is used for all values that implement typ.
+// Registered converters take precedence over the spanner.Encoder interface, but not over the types that memeduck supports natively.
+func RegisterConverter(typ reflect.Type, conv ValueConverter) {
+	internal.RegisterConverter(typ, conv)
+}
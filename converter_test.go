@@ -0,0 +1,32 @@
+package memeduck_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/genkami/memeduck"
+)
+
+type testConverterUpperName string
+
+func init() {
+	memeduck.RegisterConverter(reflect.TypeOf(testConverterUpperName("")), func(v interface{}) (interface{}, error) {
+		return strings.ToUpper(string(v.(testConverterUpperName))), nil
+	})
+}
+
+func TestRegisterConverter(t *testing.T) {
+	testSelect(t,
+		memeduck.Select("hoge", []string{"a"}).Where(
+			memeduck.Eq(memeduck.Ident("a"), testConverterUpperName("foo")),
+		),
+		`SELECT a FROM hoge WHERE a = "FOO"`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]testConverterUpperName{
+			{"foo", "bar"},
+		}),
+		`INSERT INTO hoge (a, b) VALUES ("FOO", "BAR")`,
+	)
+}
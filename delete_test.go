@@ -64,5 +64,84 @@ func TestDeleteWithMultipleWhereClause(t *testing.T) {
 
 func TestDeleteWithNoWhereClause(t *testing.T) {
 	_, err := memeduck.Delete("hoge").SQL()
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, memeduck.ErrNoWhereClause)
+}
+
+func TestDeleteWithAllRows(t *testing.T) {
+	testDelete(t,
+		memeduck.Delete("hoge").AllRows(),
+		`DELETE FROM hoge WHERE TRUE`,
+	)
+	testDelete(t,
+		memeduck.Delete("hoge").AllRows().Where(memeduck.And()),
+		`DELETE FROM hoge WHERE TRUE`,
+	)
+	testDelete(t,
+		memeduck.Delete("hoge").AllRows().Where(memeduck.Eq(memeduck.Ident("a"), 1)),
+		`DELETE FROM hoge WHERE a = 1`,
+	)
+}
+
+func TestDeleteWithUntyped(t *testing.T) {
+	testDelete(t,
+		memeduck.Delete("hoge").Where(
+			memeduck.In(memeduck.Ident("a"), memeduck.Unnest([]string{})),
+		),
+		`DELETE FROM hoge WHERE a IN UNNEST(ARRAY<STRING>[])`,
+	)
+	testDelete(t,
+		memeduck.Delete("hoge").Where(
+			memeduck.In(memeduck.Ident("a"), memeduck.Unnest([]string{})),
+		).Untyped(),
+		`DELETE FROM hoge WHERE a IN UNNEST(ARRAY[])`,
+	)
+}
+
+func TestDeleteWithEmptyConds(t *testing.T) {
+	_, err := memeduck.Delete("hoge").Where(memeduck.And(memeduck.And())).SQL()
+	assert.ErrorIs(t, err, memeduck.ErrNoWhereClause)
+	testDelete(t,
+		memeduck.Delete("hoge").Where(memeduck.And(), memeduck.Eq(memeduck.Ident("a"), 1)),
+		`DELETE FROM hoge WHERE a = 1`,
+	)
+	testDelete(t,
+		memeduck.Delete("hoge").Where(memeduck.Or()),
+		`DELETE FROM hoge WHERE FALSE`,
+	)
+}
+
+func TestDeleteWithNilConds(t *testing.T) {
+	testDelete(t,
+		memeduck.Delete("hoge").Where(nil, memeduck.Eq(memeduck.Ident("a"), 1), memeduck.When(false, memeduck.Bool(true))),
+		`DELETE FROM hoge WHERE a = 1`,
+	)
+	_, err := memeduck.Delete("hoge").Where(memeduck.EqIfNotNil(memeduck.Ident("a"), nil)).SQL()
+	assert.ErrorIs(t, err, memeduck.ErrNoWhereClause)
+}
+
+func TestDeleteWithEmptyEqMap(t *testing.T) {
+	_, err := memeduck.Delete("hoge").Where(memeduck.EqMap(map[string]interface{}{})).SQL()
+	assert.ErrorIs(t, err, memeduck.ErrNoWhereClause)
+}
+
+func TestDeleteWithZeroEqStruct(t *testing.T) {
+	type row struct {
+		ID int64
+	}
+	_, err := memeduck.Delete("hoge").Where(memeduck.EqStruct(row{})).SQL()
+	assert.ErrorIs(t, err, memeduck.ErrNoWhereClause)
+}
+
+func TestDeleteWithThenReturn(t *testing.T) {
+	testDelete(t,
+		memeduck.Delete("hoge").Where(memeduck.Eq(memeduck.Ident("a"), 1)).ThenReturn("*"),
+		`DELETE FROM hoge WHERE a = 1 THEN RETURN *`,
+	)
+	testDelete(t,
+		memeduck.Delete("hoge").Where(memeduck.Eq(memeduck.Ident("a"), 1)).ThenReturnWithAction("act", "b"),
+		`DELETE FROM hoge WHERE a = 1 THEN RETURN WITH ACTION AS act b`,
+	)
+	stmt, err := memeduck.Delete("hoge").Where(memeduck.Eq(memeduck.Ident("a"), 1)).ThenReturn("b").Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM hoge WHERE a = @p1 THEN RETURN b`, stmt.SQL)
 }
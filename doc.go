@@ -7,16 +7,25 @@ Supported Types
 The following types can be used as a SQL expression:
 
   * If a value implements `ToASTExpr() (*ast.Expr, error)`, memeduck uses this method to convert Go values into SQL expressions.
+  * If a converter for the type of a value is registered by RegisterConverter, memeduck converts the value with it and then converts the result according to these rules.
+  * If a value implements spanner.Encoder, memeduck converts the result of `EncodeSpanner()` according to these rules.
   * If a value is nil (of any type), it is converted into NULL.
   * If a value is one of string, *string, or spanner.NullString, it is converted into STRING literal.
   * If a value is []byte, it is converted into BYTES literal.
   * If a value is one of int, *int, int64, or *int64, spanner.NullInt64, it is converted into INT64 literal.
   * If a value is one of bool, *bool, or spanner.NullBool, it is converted into BOOL literal.
   * If a value is one of float64, *float64, or spanner.NullFloat64, it is converted into FLOAT64 literal.
+  * If a value is float32 or *float32, it is converted into FLOAT32 value (`CAST(... AS FLOAT32)`).
+  * Other integer, float, string, bool, and byte slice types (including named types like `type UserID int64`) are converted according to their underlying kinds. Unsigned integers that don't fit in INT64 cause an error.
   * If a value is one of time.Time, *time.Time, or spanner.NullTime, it is converted into TIMESTAMP literal.
   * If a value is one of civil.Date, *civil.Date, or spanner.NullDate, it is converted into DATE literal.
+  * If a value is one of big.Rat, *big.Rat, or spanner.NullNumeric, it is converted into NUMERIC literal. It fails if the value has more than 9 digits after the decimal point or more than 29 digits before it.
+  * If a value is a struct (or a pointer to it), it is converted into STRUCT literal like `STRUCT<Name STRING, Age INT64>("foo", 12)`. Field names follow the rules described in Struct Tags section. If the SQL type of any field can't be determined, it is converted into untyped STRUCT literal like `STRUCT("foo", 12)`.
   * If a value is a slice of the above types, it is converted into ARRAY<T> literal.
 
+If the SQL type of a NULL value or an empty array can be determined from its Go type, it is converted into a typed expression like `CAST(NULL AS STRING)` or `ARRAY<INT64>[]`.
+Use Untyped method of each statement to get bare NULL and ARRAY[] instead.
+
 
 Struct Tags
 
@@ -0,0 +1,59 @@
+package memeduck
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+// Eval evaluates cond against row in memory, so that the same condition can be applied to both Spanner and local data.
+// row must be either a struct (or a pointer to it) or map[string]interface{}.
+// Struct fields are mapped to columns in the same way as Insert.
+// params gives values of query parameters in cond, like the ones returned by ParseFilter.
+//
+// NULLs are handled with three-valued logic as in Spanner, and the row matches only if the result is TRUE.
+// Eval fails if cond contains expressions that can't be evaluated in memory, like function calls and subqueries.
+func Eval(cond WhereCond, row interface{}, params map[string]interface{}) (bool, error) {
+	column, err := rowColumnFunc(row)
+	if err != nil {
+		return false, err
+	}
+	where, err := cond.ToASTWhere()
+	if err != nil {
+		return false, err
+	}
+	e := &internal.Evaluator{Column: column, Params: params}
+	return e.EvalBool(where.Expr)
+}
+
+func rowColumnFunc(row interface{}) (func(string) (interface{}, error), error) {
+	if m, ok := row.(map[string]interface{}); ok {
+		return func(name string) (interface{}, error) {
+			v, ok := m[name]
+			if !ok {
+				return nil, errors.Errorf("row does not have column %s", name)
+			}
+			return v, nil
+		}, nil
+	}
+	rowV := reflect.ValueOf(row)
+	if rowV.Kind() == reflect.Ptr && !rowV.IsNil() {
+		rowV = rowV.Elem()
+	}
+	if rowV.Kind() != reflect.Struct {
+		return nil, errors.Errorf("%T is neither struct nor map[string]interface{}", row)
+	}
+	rowT := rowV.Type()
+	fields, err := internal.StructFields(rowT)
+	if err != nil {
+		return nil, err
+	}
+	return func(name string) (interface{}, error) {
+		if f, ok := findStructField(fields, name); ok {
+			return f.Value(rowV).Interface(), nil
+		}
+		return nil, errors.Errorf("type %s does not have column %s", rowT.String(), name)
+	}, nil
+}
@@ -0,0 +1,112 @@
+package memeduck_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck"
+)
+
+type evalRow struct {
+	ID     int64   `spanner:"id"`
+	Name   string  `spanner:"name"`
+	Race   *string `spanner:"race"`
+	Tags   []string
+	Hidden string `spanner:"-"`
+}
+
+func testEval(t *testing.T, cond memeduck.WhereCond, row interface{}, expected bool) {
+	actual, err := memeduck.Eval(cond, row, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestEvalWithStruct(t *testing.T) {
+	row := &evalRow{ID: 1, Name: "hoge", Tags: []string{"a", "b"}, Hidden: "x"}
+	testEval(t, memeduck.Eq(memeduck.Ident("id"), 1), row, true)
+	testEval(t, memeduck.Ne(memeduck.Ident("id"), 1), row, false)
+	testEval(t, memeduck.Eq(memeduck.Ident("race"), "Phoenix"), row, false)
+	testEval(t, memeduck.Ne(memeduck.Ident("race"), "Phoenix"), row, false)
+	testEval(t, memeduck.IsNull(memeduck.Ident("race")), row, true)
+	testEval(t, memeduck.IsNotNull(memeduck.Ident("race")), row, false)
+	testEval(t, memeduck.Like(memeduck.Ident("name"), "ho%"), row, true)
+	testEval(t, memeduck.NotLike(memeduck.Ident("name"), "ho%"), row, false)
+	testEval(t, memeduck.In("a", memeduck.Unnest(memeduck.Ident("tags"))), row, true)
+	testEval(t, memeduck.In(memeduck.Ident("id"), memeduck.Unnest([]int64{2, 3})), row, false)
+	testEval(t, memeduck.NotIn(memeduck.Ident("id"), memeduck.Unnest([]int64{2, 3})), row, true)
+	testEval(t, memeduck.Between(memeduck.Ident("id"), 0, 1), row, true)
+	testEval(t, memeduck.NotBetween(memeduck.Ident("id"), 0, 1), row, false)
+	testEval(t, memeduck.Bool(true), row, true)
+	testEval(t, memeduck.And(), row, true)
+	testEval(t, memeduck.Or(), row, false)
+	testEval(t,
+		memeduck.And(
+			memeduck.Eq(memeduck.Ident("id"), 1),
+			memeduck.Or(memeduck.EqIfNotNil(memeduck.Ident("race"), nil)),
+		),
+		row, true,
+	)
+	testEval(t,
+		memeduck.Or(
+			memeduck.Eq(memeduck.Ident("race"), "Phoenix"),
+			memeduck.And(memeduck.Eq(memeduck.Ident("id"), 1), memeduck.Eq(memeduck.Ident("name"), "hoge")),
+		),
+		row, true,
+	)
+	testEval(t, memeduck.EqStruct(evalRow{ID: 1, Name: "hoge"}), row, true)
+	testEval(t, memeduck.EqStruct(evalRow{ID: 1}).IncludeZero("race"), row, true)
+}
+
+func TestEvalWithEmbeddedStruct(t *testing.T) {
+	row := &testInsertGoStructWithEmbedded{ID: 1, testAuditFields: &testAuditFields{testTimestamps{CreatedAt: 2}, "foo"}}
+	testEval(t, memeduck.Eq(memeduck.Ident("created_at"), 2), row, true)
+	testEval(t, memeduck.Eq(memeduck.Ident("updated_at"), ""), row, true)
+	testEval(t, memeduck.IsNull(memeduck.Ident("updated_by")), testInsertGoStructWithEmbedded{}, true)
+}
+
+func TestEvalWithMap(t *testing.T) {
+	row := map[string]interface{}{
+		"id":   int32(1),
+		"name": "hoge",
+		"race": nil,
+	}
+	testEval(t, memeduck.Eq(memeduck.Ident("id"), 1), row, true)
+	testEval(t, memeduck.Eq(memeduck.Ident("race"), "Phoenix"), row, false)
+	testEval(t, memeduck.EqMap(map[string]interface{}{"id": []int{1, 2}, "race": nil}), row, true)
+	testEval(t, memeduck.EqMap(map[string]interface{}{"id": 1, "name": "fuga"}), row, false)
+}
+
+func TestEvalWithParams(t *testing.T) {
+	row := &evalRow{ID: 1, Name: "hoge", Tags: []string{"a", "b"}}
+	actual, err := memeduck.Eval(memeduck.Eq(memeduck.Ident("id"), memeduck.Param("id")), row, map[string]interface{}{"id": 1})
+	assert.Nil(t, err)
+	assert.True(t, actual)
+	cond, params, err := memeduck.ParseFilter(`id >= 1 AND (name LIKE "f%" OR tags CONTAINS "b")`, allowAllColumns)
+	assert.Nil(t, err)
+	actual, err = memeduck.Eval(cond, row, params)
+	assert.Nil(t, err)
+	assert.True(t, actual)
+	_, err = memeduck.Eval(memeduck.Eq(memeduck.Ident("id"), memeduck.Param("id")), row, nil)
+	assert.Error(t, err, "unbound parameter")
+}
+
+func TestEvalWithNaN(t *testing.T) {
+	row := map[string]interface{}{"score": math.NaN()}
+	testEval(t, memeduck.Eq(memeduck.Ident("score"), math.NaN()), row, false)
+	testEval(t, memeduck.Ne(memeduck.Ident("score"), math.NaN()), row, true)
+	testEval(t, memeduck.Op(memeduck.Ident("score"), memeduck.LE, 1.5), row, false)
+	testEval(t, memeduck.Op(memeduck.Ident("score"), memeduck.GT, 1.5), row, false)
+}
+
+func TestEvalWithInvalidArgs(t *testing.T) {
+	_, err := memeduck.Eval(memeduck.Eq(memeduck.Ident("id"), 1), 1, nil)
+	assert.Error(t, err, "invalid row")
+	_, err = memeduck.Eval(memeduck.Eq(memeduck.Ident("Hidden"), 1), evalRow{}, nil)
+	assert.Error(t, err, "ignored column")
+	_, err = memeduck.Eval(memeduck.Eq(memeduck.Ident("unknown"), 1), map[string]interface{}{}, nil)
+	assert.Error(t, err, "unknown column")
+	_, err = memeduck.Eval(memeduck.Eq(memeduck.Ident("id"), "1"), map[string]interface{}{"id": 1}, nil)
+	assert.Error(t, err, "type mismatch")
+}
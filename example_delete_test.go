@@ -31,3 +31,9 @@ func ExampleDelete_queryParameter() {
 	fmt.Println(query)
 	// Output: DELETE FROM user WHERE id = @id
 }
+
+func ExampleDeleteStmt_AllRows() {
+	query, _ := memeduck.Delete("user").AllRows().SQL()
+	fmt.Println(query)
+	// Output: DELETE FROM user WHERE TRUE
+}
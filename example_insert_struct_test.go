@@ -2,6 +2,7 @@ package memeduck_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/genkami/memeduck"
 )
@@ -18,3 +19,28 @@ func ExampleInsert_struct() {
 	fmt.Println(query)
 	// Output: INSERT INTO users (UserName, PapaName) VALUES ("Kiara", "huke")
 }
+
+func ExampleInsertStruct() {
+	query, _ := memeduck.InsertStruct("users", []*ExampleUserStruct{
+		{Name: "Kiara", Papa: "huke"},
+		{Name: "Calli", Papa: "Yukisame"},
+	}).SQL()
+	fmt.Println(query)
+	// Output: INSERT INTO users (UserName, PapaName) VALUES ("Kiara", "huke"), ("Calli", "Yukisame")
+}
+
+type ExampleUserWithOptions struct {
+	ID        int64     `spanner:"UserID"`
+	Name      string    `spanner:"UserName,default"`
+	NameLower string    `spanner:"UserNameLower,generated"`
+	CreatedAt time.Time `spanner:"CreatedAt,commit_ts"`
+}
+
+func ExampleInsertStruct_tagOptions() {
+	query, _ := memeduck.InsertStruct("users", []*ExampleUserWithOptions{
+		{ID: 1, Name: "Kiara"},
+		{ID: 2},
+	}).SQL()
+	fmt.Println(query)
+	// Output: INSERT INTO users (UserID, UserName, CreatedAt) VALUES (1, "Kiara", PENDING_COMMIT_TIMESTAMP()), (2, DEFAULT, PENDING_COMMIT_TIMESTAMP())
+}
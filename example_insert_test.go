@@ -1,7 +1,12 @@
 package memeduck_test
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/api/iterator"
 
 	"github.com/genkami/memeduck"
 )
@@ -22,3 +27,63 @@ func ExampleInsert_queryParameter() {
 	fmt.Println(query)
 	// Output: INSERT INTO user (name, weight, is_onion) VALUES (@name, @weight, @is_onion)
 }
+
+func ExampleInsertStmt_Select() {
+	query, _ := memeduck.Insert("graduated_user", []string{"name", "oshi_mark"}).Select(
+		memeduck.Select("user", []string{"name", "oshi_mark"}).Where(memeduck.IsNotNull(memeduck.Ident("graduated_at"))),
+	).SQL()
+	fmt.Println(query)
+	// Output: INSERT INTO graduated_user (name, oshi_mark) SELECT name, oshi_mark FROM user WHERE graduated_at IS NOT NULL
+}
+
+func ExampleInsertStmt_OrUpdate() {
+	query, _ := memeduck.Insert("user", []string{"name", "oshi_mark"}).Values([][]string{
+		{"Subaru", ":ambulance:"},
+	}).OrUpdate().SQL()
+	fmt.Println(query)
+	// Output: INSERT OR UPDATE INTO user (name, oshi_mark) VALUES ("Subaru", ":ambulance:")
+}
+
+func ExampleInsertStmt_ThenReturn() {
+	query, _ := memeduck.Insert("user", []string{"name"}).Values([][]string{
+		{"Subaru"},
+	}).ThenReturn("id", "name").SQL()
+	fmt.Println(query)
+	// Output: INSERT INTO user (name) VALUES ("Subaru") THEN RETURN id, name
+}
+
+func ExampleInsertStmt_Batch() {
+	stmts, _ := memeduck.Insert("user", []string{"name", "oshi_mark"}).Values([][]string{
+		{"Subaru", ":ambulance:"},
+		{"Watame", ":sheep:"},
+		{"Towa", ":devil:"},
+	}).Batch(memeduck.BatchLimits{MaxMutations: 4})
+	for _, stmt := range stmts {
+		fmt.Println(stmt.SQL)
+	}
+	// Output:
+	// INSERT INTO user (name, oshi_mark) VALUES (@p1, @p2), (@p3, @p4)
+	// INSERT INTO user (name, oshi_mark) VALUES (@p1, @p2)
+}
+
+func ExampleInsertStmt_BatchRows() {
+	r := csv.NewReader(strings.NewReader("Subaru,:ambulance:\nWatame,:sheep:\nTowa,:devil:\n"))
+	rows := memeduck.RowIteratorFunc(func() (interface{}, error) {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil, iterator.Done
+		}
+		return record, err
+	})
+	it := memeduck.Insert("user", []string{"name", "oshi_mark"}).BatchRows(rows, memeduck.BatchLimits{MaxRows: 2})
+	for {
+		stmt, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		fmt.Println(stmt.SQL, stmt.Params)
+	}
+	// Output:
+	// INSERT INTO user (name, oshi_mark) VALUES (@p1, @p2), (@p3, @p4) map[p1:Subaru p2::ambulance: p3:Watame p4::sheep:]
+	// INSERT INTO user (name, oshi_mark) VALUES (@p1, @p2) map[p1:Towa p2::devil:]
+}
@@ -108,3 +108,63 @@ func ExampleSelect_queryMultiSubQuery() {
 	fmt.Println(query)
 	// Output: SELECT name, ARRAY(SELECT AS STRUCT item_id, count FROM user_item WHERE user_id = "user-id") AS user_item, ARRAY(SELECT AS STRUCT state FROM user_status WHERE user_id = "user-id") AS user_status FROM user WHERE user_id = "user-id"
 }
+
+func ExampleSelectStmt_Statement() {
+	stmt, _ := memeduck.Select("user", []string{"name"}).
+		Where(
+			memeduck.Eq(memeduck.Ident("name"), "Calliope"),
+			memeduck.Ge(memeduck.Ident("age"), 1000),
+		).
+		Statement()
+	fmt.Println(stmt.SQL)
+	fmt.Println(stmt.Params)
+	// Output:
+	// SELECT name FROM user WHERE name = @p1 AND age >= @p2
+	// map[p1:Calliope p2:1000]
+}
+
+func ExampleEqIfNotNil() {
+	var race *string
+	minAge := 18
+	query, _ := memeduck.Select("user", []string{"name"}).Where(
+		memeduck.EqIfNotNil(memeduck.Ident("race"), race),
+		memeduck.When(minAge > 0, memeduck.Ge(memeduck.Ident("age"), minAge)),
+	).SQL()
+	fmt.Println(query)
+	// Output: SELECT name FROM user WHERE age >= 18
+}
+
+func ExampleEqMap() {
+	query, _ := memeduck.Select("user", []string{"name"}).Where(
+		memeduck.EqMap(map[string]interface{}{
+			"race":     "Phoenix",
+			"work_at":  []string{"KFP", "holoEN"},
+			"graduate": nil,
+		}),
+	).SQL()
+	fmt.Println(query)
+	// Output: SELECT name FROM user WHERE graduate IS NULL AND race = "Phoenix" AND work_at IN UNNEST(ARRAY["KFP", "holoEN"])
+}
+
+func ExampleEqStruct() {
+	type User struct {
+		Name   string `spanner:"name"`
+		Race   string `spanner:"race"`
+		Banned bool   `spanner:"banned"`
+	}
+	query, _ := memeduck.Select("user", []string{"name"}).Where(
+		memeduck.EqStruct(&User{Race: "Phoenix"}).IncludeZero("banned"),
+	).SQL()
+	fmt.Println(query)
+	// Output: SELECT name FROM user WHERE race = "Phoenix" AND banned = FALSE
+}
+
+func ExampleParseFilter() {
+	cond, params, _ := memeduck.ParseFilter(`age >= 18 AND (name LIKE "a%" OR tags CONTAINS "x")`, memeduck.AllowColumns("age", "name", "tags"))
+	stmt, _ := memeduck.Select("user", []string{"name"}).Where(cond).Params(params).Statement()
+	fmt.Println(stmt.SQL)
+	fmt.Println(stmt.Params)
+	// Output:
+	// SELECT name FROM user WHERE age >= @filter1 AND (name LIKE @filter2 OR @filter3 IN UNNEST(tags))
+	// map[filter1:18 filter2:a% filter3:x]
+}
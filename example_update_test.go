@@ -3,6 +3,8 @@ package memeduck_test
 import (
 	"fmt"
 
+	"cloud.google.com/go/spanner"
+
 	"github.com/genkami/memeduck"
 )
 
@@ -37,3 +39,26 @@ func ExampleUpdate_queryParameter() {
 	fmt.Println(query)
 	// Output: UPDATE user SET age = @age WHERE shark = TRUE
 }
+
+func ExampleUpdateStmt_Params() {
+	stmt, _ := memeduck.Update("user").
+		Set(memeduck.Ident("age"), memeduck.Param("age")).
+		Where(memeduck.Eq(memeduck.Ident("name"), "Gura")).
+		Params(map[string]interface{}{"age": 9000}).
+		Statement()
+	fmt.Println(stmt.SQL)
+	fmt.Println(stmt.Params)
+	// Output:
+	// UPDATE user SET age = @age WHERE name = @p1
+	// map[age:9000 p1:Gura]
+}
+
+func ExampleUpdate_default() {
+	query, _ := memeduck.Update("user").
+		Set(memeduck.Ident("nickname"), memeduck.Default).
+		Set(memeduck.Ident("updated_at"), spanner.CommitTimestamp).
+		Where(memeduck.Eq(memeduck.Ident("name"), "Ame")).
+		SQL()
+	fmt.Println(query)
+	// Output: UPDATE user SET nickname = DEFAULT, updated_at = PENDING_COMMIT_TIMESTAMP() WHERE name = "Ame"
+}
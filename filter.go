@@ -0,0 +1,476 @@
+package memeduck
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// ParseFilter parses a filter expression and converts it into WhereCond.
+// Literals in src are not embedded in the result but bound to query parameters named @filter1, @filter2, ...,
+// so the returned map must be passed to Params of the statement.
+// Each identifier in src is checked by allow so that untrusted input can't refer to arbitrary columns.
+// allow must not be nil; pass a function that always returns true to accept all identifiers.
+//
+// The filter expression consists of the following:
+//
+//	x = y, x != y, x <> y, x < y, x > y, x <= y, x >= y
+//	x LIKE y, x NOT LIKE y
+//	x IS NULL, x IS NOT NULL
+//	x IN (a, b, ...), x NOT IN (a, b, ...)
+//	x BETWEEN a AND b, x NOT BETWEEN a AND b
+//	x CONTAINS a    (which means that the array x contains a)
+//	cond AND cond, cond OR cond, (cond)
+//
+// Operands are either identifiers like `age` or `user.name`, or literals like `"foo"`, `'foo'`, `123`, `-1.5`, `TRUE` and `FALSE`.
+// Keywords are case-insensitive.
+func ParseFilter(src string, allow ColumnFilter) (WhereCond, map[string]interface{}, error) {
+	if allow == nil {
+		return nil, nil, errors.New("filter: no ColumnFilter specified")
+	}
+	tokens, err := lexFilter(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &filterParser{
+		tokens: tokens,
+		allow:  allow,
+		params: map[string]interface{}{},
+	}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tok := p.peek(); tok.kind != filterTokenEOF {
+		return nil, nil, p.unexpected(tok)
+	}
+	return cond, p.params, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenIdent
+	filterTokenKeyword
+	filterTokenString
+	filterTokenNumber
+	filterTokenSymbol
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string // keywords are upper-cased, and strings are unquoted
+	pos  int
+}
+
+var filterKeywords = map[string]bool{
+	"AND":      true,
+	"OR":       true,
+	"NOT":      true,
+	"IS":       true,
+	"NULL":     true,
+	"LIKE":     true,
+	"IN":       true,
+	"BETWEEN":  true,
+	"CONTAINS": true,
+	"TRUE":     true,
+	"FALSE":    true,
+}
+
+func lexFilter(src string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			text := string(runes[start:i])
+			if upper := strings.ToUpper(text); filterKeywords[upper] {
+				tokens = append(tokens, filterToken{kind: filterTokenKeyword, text: upper, pos: start})
+			} else {
+				tokens = append(tokens, filterToken{kind: filterTokenIdent, text: text, pos: start})
+			}
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenNumber, text: string(runes[start:i]), pos: start})
+		case r == '"' || r == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, errors.Errorf("filter: unterminated string at %d", start)
+				}
+				if runes[i] == r {
+					i++
+					break
+				}
+				if runes[i] == '\\' {
+					i++
+					if i >= len(runes) {
+						return nil, errors.Errorf("filter: unterminated string at %d", start)
+					}
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenString, text: b.String(), pos: start})
+		default:
+			start := i
+			text := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "!=", "<>", "<=", ">=":
+					text = two
+				}
+			}
+			switch text {
+			case "=", "!=", "<>", "<", ">", "<=", ">=", "(", ")", ",", ".", "-":
+			default:
+				return nil, errors.Errorf("filter: unexpected character %q at %d", r, start)
+			}
+			i += len([]rune(text))
+			tokens = append(tokens, filterToken{kind: filterTokenSymbol, text: text, pos: start})
+		}
+	}
+	tokens = append(tokens, filterToken{kind: filterTokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	allow  ColumnFilter
+	params map[string]interface{}
+}
+
+var filterComparisonOps = map[string]BinaryOp{
+	"=":  EQ,
+	"!=": NE,
+	"<>": NE,
+	"<":  LT,
+	">":  GT,
+	"<=": LE,
+	">=": GE,
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != filterTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the given keyword or symbol.
+func (p *filterParser) accept(text string) bool {
+	tok := p.peek()
+	if (tok.kind == filterTokenKeyword || tok.kind == filterTokenSymbol) && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected(p.peek())
+	}
+	return nil
+}
+
+func (p *filterParser) unexpected(tok filterToken) error {
+	if tok.kind == filterTokenEOF {
+		return errors.New("filter: unexpected end of input")
+	}
+	return errors.Errorf("filter: unexpected %q at %d", tok.text, tok.pos)
+}
+
+func (p *filterParser) parseOr() (WhereCond, error) {
+	cond, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	conds := []WhereCond{cond}
+	for p.accept("OR") {
+		cond, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return Or(conds...), nil
+}
+
+func (p *filterParser) parseAnd() (WhereCond, error) {
+	cond, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	conds := []WhereCond{cond}
+	for p.accept("AND") {
+		cond, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return And(conds...), nil
+}
+
+func (p *filterParser) parsePrimary() (WhereCond, error) {
+	if p.accept("(") {
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return cond, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *filterParser) parsePredicate() (WhereCond, error) {
+	lhs, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	if op, ok := filterComparisonOps[tok.text]; ok && tok.kind == filterTokenSymbol {
+		p.next()
+		rhs, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return Op(lhs, op, rhs), nil
+	}
+	if p.accept("IS") {
+		not := p.accept("NOT")
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return &NullCond{arg: lhs, not: not}, nil
+	}
+	if p.accept("CONTAINS") {
+		if _, ok := lhs.(*IdentExpr); !ok {
+			return nil, errors.Errorf("filter: left hand side of CONTAINS must be an identifier at %d", tok.pos)
+		}
+		elem, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return In(elem, Unnest(lhs)), nil
+	}
+	not := p.accept("NOT")
+	switch {
+	case p.accept("LIKE"):
+		rhs, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if not {
+			return NotLike(lhs, rhs), nil
+		}
+		return Like(lhs, rhs), nil
+	case p.accept("IN"):
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &InCond{lhs: lhs, rhs: Unnest(list), not: not}, nil
+	case p.accept("BETWEEN"):
+		min, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		max, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &BetweenCond{arg: lhs, min: min, max: max, not: not}, nil
+	default:
+		return nil, p.unexpected(p.peek())
+	}
+}
+
+// parseList parses a list of literals like `(1, 2, 3)` and binds it to a query parameter as an array.
+func (p *filterParser) parseList() (*ParamExpr, error) {
+	start := p.peek()
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for {
+		v, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if p.accept(")") {
+			break
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+	list, err := filterListValue(values)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "filter: invalid list at %d", start.pos)
+	}
+	return p.bind(list), nil
+}
+
+// filterListValue converts values into a typed slice. Integers are converted into floats if there are both of them.
+func filterListValue(values []interface{}) (interface{}, error) {
+	var nInt, nFloat, nString, nBool int
+	for _, v := range values {
+		switch v.(type) {
+		case int64:
+			nInt++
+		case float64:
+			nFloat++
+		case string:
+			nString++
+		case bool:
+			nBool++
+		}
+	}
+	switch len(values) {
+	case nInt:
+		list := make([]int64, 0, len(values))
+		for _, v := range values {
+			list = append(list, v.(int64))
+		}
+		return list, nil
+	case nInt + nFloat:
+		list := make([]float64, 0, len(values))
+		for _, v := range values {
+			if i, ok := v.(int64); ok {
+				list = append(list, float64(i))
+			} else {
+				list = append(list, v.(float64))
+			}
+		}
+		return list, nil
+	case nString:
+		list := make([]string, 0, len(values))
+		for _, v := range values {
+			list = append(list, v.(string))
+		}
+		return list, nil
+	case nBool:
+		list := make([]bool, 0, len(values))
+		for _, v := range values {
+			list = append(list, v.(bool))
+		}
+		return list, nil
+	default:
+		return nil, errors.New("elements must have the same type")
+	}
+}
+
+func (p *filterParser) parseOperand() (interface{}, error) {
+	tok := p.peek()
+	if tok.kind != filterTokenIdent {
+		v, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return p.bind(v), nil
+	}
+	names := []string{p.next().text}
+	for p.accept(".") {
+		tok := p.next()
+		if tok.kind != filterTokenIdent {
+			return nil, p.unexpected(tok)
+		}
+		names = append(names, tok.text)
+	}
+	if !p.allow(names) {
+		return nil, errors.Errorf("filter: column %s is not allowed at %d", strings.Join(names, "."), tok.pos)
+	}
+	return Ident(names...), nil
+}
+
+func (p *filterParser) parseLiteral() (interface{}, error) {
+	tok := p.next()
+	switch tok.kind {
+	case filterTokenString:
+		return tok.text, nil
+	case filterTokenNumber:
+		return parseFilterNumber(tok, false)
+	case filterTokenKeyword:
+		switch tok.text {
+		case "TRUE":
+			return true, nil
+		case "FALSE":
+			return false, nil
+		}
+	case filterTokenSymbol:
+		if tok.text == "-" && p.peek().kind == filterTokenNumber {
+			return parseFilterNumber(p.next(), true)
+		}
+	}
+	return nil, p.unexpected(tok)
+}
+
+func parseFilterNumber(tok filterToken, neg bool) (interface{}, error) {
+	text := tok.text
+	if neg {
+		text = "-" + text
+	}
+	if !strings.ContainsAny(text, ".eE") {
+		v, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("filter: invalid integer %s at %d", text, tok.pos)
+		}
+		return v, nil
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, errors.Errorf("filter: invalid number %s at %d", text, tok.pos)
+	}
+	return v, nil
+}
+
+func (p *filterParser) bind(v interface{}) *ParamExpr {
+	name := "filter" + strconv.Itoa(len(p.params)+1)
+	p.params[name] = v
+	return Param(name)
+}
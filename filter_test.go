@@ -0,0 +1,115 @@
+package memeduck_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck"
+)
+
+func allowAllColumns(ident []string) bool {
+	return true
+}
+
+func testFilter(t *testing.T, src string, expected string, expectedParams map[string]interface{}) {
+	cond, params, err := memeduck.ParseFilter(src, allowAllColumns)
+	if !assert.Nil(t, err, src) {
+		return
+	}
+	testWhere(t, cond, expected)
+	assert.Equal(t, expectedParams, params, src)
+}
+
+func TestParseFilter(t *testing.T) {
+	testFilter(t, `age >= 18`, `age >= @filter1`, map[string]interface{}{"filter1": int64(18)})
+	testFilter(t, `18 < age`, `@filter1 < age`, map[string]interface{}{"filter1": int64(18)})
+	testFilter(t, `a = "x" and b != 'y' AND c <> -1.5`,
+		`a = @filter1 AND b != @filter2 AND c != @filter3`,
+		map[string]interface{}{"filter1": "x", "filter2": "y", "filter3": -1.5},
+	)
+	testFilter(t, `user.name LIKE "a\"%" OR user.name not like "b%"`,
+		`user.name LIKE @filter1 OR user.name NOT LIKE @filter2`,
+		map[string]interface{}{"filter1": `a"%`, "filter2": "b%"},
+	)
+	testFilter(t, `a IS NULL AND b is not null`, `a IS NULL AND b IS NOT NULL`, map[string]interface{}{})
+	testFilter(t, `a = TRUE or a = false`,
+		`a = @filter1 OR a = @filter2`,
+		map[string]interface{}{"filter1": true, "filter2": false},
+	)
+	testFilter(t, `a IN (1, 2) AND b NOT IN (1, 2.5) AND c IN ("x")`,
+		`a IN UNNEST(@filter1) AND b NOT IN UNNEST(@filter2) AND c IN UNNEST(@filter3)`,
+		map[string]interface{}{"filter1": []int64{1, 2}, "filter2": []float64{1, 2.5}, "filter3": []string{"x"}},
+	)
+	testFilter(t, `a BETWEEN 1 AND 10 AND b NOT BETWEEN 1e3 AND 2E+3`,
+		`a BETWEEN @filter1 AND @filter2 AND b NOT BETWEEN @filter3 AND @filter4`,
+		map[string]interface{}{"filter1": int64(1), "filter2": int64(10), "filter3": 1e3, "filter4": 2e3},
+	)
+	testFilter(t, `tags CONTAINS "x"`, `@filter1 IN UNNEST(tags)`, map[string]interface{}{"filter1": "x"})
+	testFilter(t, `age >= 18 AND (name LIKE "a%" OR tags CONTAINS "x")`,
+		`age >= @filter1 AND (name LIKE @filter2 OR @filter3 IN UNNEST(tags))`,
+		map[string]interface{}{"filter1": int64(18), "filter2": "a%", "filter3": "x"},
+	)
+	testFilter(t, `((a = 1))`, `a = @filter1`, map[string]interface{}{"filter1": int64(1)})
+}
+
+func TestParseFilterWithAllowList(t *testing.T) {
+	allow := memeduck.AllowColumns("age", "name")
+	_, _, err := memeduck.ParseFilter(`age >= 18 AND name = "x"`, allow)
+	assert.Nil(t, err)
+	for _, src := range []string{
+		`password = "x"`,
+		`age >= 18 OR password IS NULL`,
+		`"x" = password`,
+		`name.age = 1`,
+		`tags CONTAINS "x"`,
+	} {
+		_, _, err := memeduck.ParseFilter(src, allow)
+		assert.Error(t, err, src)
+	}
+	_, _, err = memeduck.ParseFilter(`age >= 18`, nil)
+	assert.Error(t, err)
+}
+
+func TestParseFilterWithInvalidInput(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`a`,
+		`a =`,
+		`a = = 1`,
+		`a = b c`,
+		`(a = 1`,
+		`a = 1)`,
+		`a = "x`,
+		`a = "x\`,
+		`a = 1.2.3`,
+		`a = 99999999999999999999`,
+		`a ; DROP TABLE user`,
+		`a IN ()`,
+		`a IN (1, "x")`,
+		`a IN (b)`,
+		`a IS 1`,
+		`a NOT = 1`,
+		`a BETWEEN 1 OR 2`,
+		`"x" CONTAINS "x"`,
+		`a = NULL`,
+		`AND = 1`,
+		`a = -b`,
+		`a. = 1`,
+	} {
+		_, _, err := memeduck.ParseFilter(src, allowAllColumns)
+		assert.Error(t, err, src)
+	}
+}
+
+func TestParseFilterWithStatement(t *testing.T) {
+	cond, params, err := memeduck.ParseFilter(`age >= 18 AND name LIKE "a%"`, allowAllColumns)
+	assert.Nil(t, err)
+	stmt, err := memeduck.Select("user", []string{"name"}).
+		Where(memeduck.Eq(memeduck.Ident("race"), "Phoenix"), cond).
+		Params(params).
+		Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT name FROM user WHERE race = @p1 AND age >= @filter1 AND name LIKE @filter2`, stmt.SQL)
+	assert.Equal(t, map[string]interface{}{"p1": "Phoenix", "filter1": int64(18), "filter2": "a%"}, stmt.Params)
+}
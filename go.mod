@@ -8,4 +8,5 @@ require (
 	github.com/MakeNowJust/memefish v0.0.0-20211014154734-dbfb8b28907d
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.7.0
+	google.golang.org/api v0.74.0
 )
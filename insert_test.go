@@ -58,7 +58,7 @@ func TestInsertWithStringSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][][]string{
 			{{}, {"a"}, {"b", "c"}},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (ARRAY[], ARRAY["a"], ARRAY["b", "c"])`,
+		`INSERT INTO hoge (a, b) VALUES (ARRAY<STRING>[], ARRAY["a"], ARRAY["b", "c"])`,
 	)
 }
 
@@ -75,7 +75,7 @@ func TestInsertWithStringPtrSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]*string{
 			{nil, nil},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS STRING), CAST(NULL AS STRING))`,
 	)
 }
 
@@ -86,7 +86,7 @@ func TestInsertWithStringPtrSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]*string{
 			{{}, {&a}, {&b, nil}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY["foo"], ARRAY["bar", NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<STRING>[], ARRAY["foo"], ARRAY["bar", NULL])`,
 	)
 }
 
@@ -104,7 +104,7 @@ func TestInsertWithNullStringSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]spanner.NullString{
 			{null, null},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS STRING), CAST(NULL AS STRING))`,
 	)
 }
 
@@ -116,7 +116,7 @@ func TestInsertWithNullStringSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]spanner.NullString{
 			{{}, {a}, {b, null}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY["foo"], ARRAY["bar", NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<STRING>[], ARRAY["foo"], ARRAY["bar", NULL])`,
 	)
 }
 
@@ -131,7 +131,7 @@ func TestInsertWithByteSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][][]byte{
 			{nil, nil},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS BYTES), CAST(NULL AS BYTES))`,
 	)
 }
 
@@ -140,7 +140,7 @@ func TestInsertWithByteSliceSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][][]byte{
 			{{}, {{0, 1}}, {{2, 3, 4}, nil}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[B"\x00\x01"], ARRAY[B"\x02\x03\x04", NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<BYTES>[], ARRAY[B"\x00\x01"], ARRAY[B"\x02\x03\x04", NULL])`,
 	)
 }
 
@@ -158,7 +158,7 @@ func TestInsertWithIntSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]int{
 			{{}, {123}, {456, 789}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[123], ARRAY[456, 789])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<INT64>[], ARRAY[123], ARRAY[456, 789])`,
 	)
 }
 
@@ -175,7 +175,7 @@ func TestInsertWithIntPtrSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]*int{
 			{nil, nil},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS INT64), CAST(NULL AS INT64))`,
 	)
 }
 
@@ -186,7 +186,7 @@ func TestInsertWithIntPtrSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]*int{
 			{{}, {&a}, {&b, nil}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[123], ARRAY[456, NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<INT64>[], ARRAY[123], ARRAY[456, NULL])`,
 	)
 }
 
@@ -204,7 +204,7 @@ func TestInsertWithInt64SliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]int64{
 			{{}, {123}, {456, 789}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[123], ARRAY[456, 789])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<INT64>[], ARRAY[123], ARRAY[456, 789])`,
 	)
 }
 
@@ -221,7 +221,7 @@ func TestInsertWithInt64PtrSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]*int64{
 			{nil, nil},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS INT64), CAST(NULL AS INT64))`,
 	)
 }
 
@@ -232,7 +232,7 @@ func TestInsertWithInt64PtrSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]*int64{
 			{{}, {&a}, {&b, nil}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[123], ARRAY[456, NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<INT64>[], ARRAY[123], ARRAY[456, NULL])`,
 	)
 }
 
@@ -250,7 +250,7 @@ func TestInsertWithNullInt64Slice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]spanner.NullInt64{
 			{null, null},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS INT64), CAST(NULL AS INT64))`,
 	)
 }
 
@@ -262,7 +262,7 @@ func TestInsertWithNullInt64SliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]spanner.NullInt64{
 			{{}, {a}, {b, null}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[123], ARRAY[456, NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<INT64>[], ARRAY[123], ARRAY[456, NULL])`,
 	)
 }
 
@@ -280,7 +280,7 @@ func TestInsertWithBoolSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]bool{
 			{{}, {true}, {false, true}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[TRUE], ARRAY[FALSE, TRUE])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<BOOL>[], ARRAY[TRUE], ARRAY[FALSE, TRUE])`,
 	)
 }
 
@@ -297,7 +297,7 @@ func TestInsertWithBoolPtrSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]*bool{
 			{nil, nil},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS BOOL), CAST(NULL AS BOOL))`,
 	)
 }
 
@@ -308,7 +308,7 @@ func TestInsertWithBoolPtrSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]*bool{
 			{{}, {&a}, {&b, nil}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[TRUE], ARRAY[FALSE, NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<BOOL>[], ARRAY[TRUE], ARRAY[FALSE, NULL])`,
 	)
 }
 
@@ -326,7 +326,7 @@ func TestInsertWithNullBoolSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]spanner.NullBool{
 			{null, null},
 		}),
-		`INSERT INTO hoge (a, b) VALUES (NULL, NULL)`,
+		`INSERT INTO hoge (a, b) VALUES (CAST(NULL AS BOOL), CAST(NULL AS BOOL))`,
 	)
 }
 
@@ -338,7 +338,7 @@ func TestInsertWithNullBoolSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]spanner.NullBool{
 			{{}, {a}, {b, null}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[TRUE], ARRAY[FALSE, NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<BOOL>[], ARRAY[TRUE], ARRAY[FALSE, NULL])`,
 	)
 }
 
@@ -369,7 +369,7 @@ func TestInsertWithFloat64SliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]float64{
 			{{}, {0}, {31.5, math.Inf(1)}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[0e+00], ARRAY[3.15e+01, +Inf])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<FLOAT64>[], ARRAY[0e+00], ARRAY[3.15e+01, +Inf])`,
 	)
 }
 
@@ -391,7 +391,7 @@ func TestInsertWithFloat64PtrSlice(t *testing.T) {
 			`NaN, `+
 			`+Inf, `+
 			`-Inf, `+
-			`NULL)`,
+			`CAST(NULL AS FLOAT64))`,
 	)
 }
 
@@ -402,7 +402,7 @@ func TestInsertWithFloat64PtrSliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]*float64{
 			{{}, {&a}, {&b, nil}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[0e+00], ARRAY[3.15e+01, NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<FLOAT64>[], ARRAY[0e+00], ARRAY[3.15e+01, NULL])`,
 	)
 }
 
@@ -425,7 +425,7 @@ func TestInsertWithNullFloat64Slice(t *testing.T) {
 			`NaN, `+
 			`+Inf, `+
 			`-Inf, `+
-			`NULL)`,
+			`CAST(NULL AS FLOAT64))`,
 	)
 }
 
@@ -437,7 +437,7 @@ func TestInsertWithNullFloat64SliceSlice(t *testing.T) {
 		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][][]spanner.NullFloat64{
 			{{}, {a}, {b, null}},
 		}),
-		`INSERT INTO hoge (a, b, c) VALUES (ARRAY[], ARRAY[0e+00], ARRAY[3.15e+01, NULL])`,
+		`INSERT INTO hoge (a, b, c) VALUES (ARRAY<FLOAT64>[], ARRAY[0e+00], ARRAY[3.15e+01, NULL])`,
 	)
 }
 
@@ -467,7 +467,7 @@ func TestInsertWithTimeSliceSlice(t *testing.T) {
 			{{}, {a}, {b, c}},
 		}),
 		`INSERT INTO hoge (a, b, c) VALUES (`+
-			`ARRAY[], `+
+			`ARRAY<TIMESTAMP>[], `+
 			`ARRAY[TIMESTAMP "2020-06-06T12:34:56.123456Z"], `+
 			`ARRAY[TIMESTAMP "2021-08-10T00:01:23.456789+09:00", `+
 			`TIMESTAMP "2022-12-08T14:22:51.837583-04:30"])`,
@@ -488,7 +488,7 @@ func TestInsertWithTimePtrSlice(t *testing.T) {
 			`TIMESTAMP "2021-08-10T00:01:23.456789+09:00", `+
 			`TIMESTAMP "2022-12-08T14:22:51.837583-04:30", `+
 			`TIMESTAMP "2023-10-10T08:43:17.536829Z", `+
-			`NULL)`,
+			`CAST(NULL AS TIMESTAMP))`,
 	)
 }
 
@@ -500,7 +500,7 @@ func TestInsertWithTimePtrSliceSlice(t *testing.T) {
 			{{}, {&a}, {&b, nil}},
 		}),
 		`INSERT INTO hoge (a, b, c) VALUES (`+
-			`ARRAY[], `+
+			`ARRAY<TIMESTAMP>[], `+
 			`ARRAY[TIMESTAMP "2020-06-06T12:34:56.123456Z"], `+
 			`ARRAY[TIMESTAMP "2021-08-10T00:01:23.456789+09:00", NULL])`,
 	)
@@ -521,7 +521,7 @@ func TestInsertWithNullTimeSlice(t *testing.T) {
 			`TIMESTAMP "2021-08-10T00:01:23.456789+09:00", `+
 			`TIMESTAMP "2022-12-08T14:22:51.837583-04:30", `+
 			`TIMESTAMP "2023-10-10T08:43:17.536829Z", `+
-			`NULL)`,
+			`CAST(NULL AS TIMESTAMP))`,
 	)
 }
 
@@ -534,7 +534,7 @@ func TestInsertWithNullTimeSliceSlice(t *testing.T) {
 			{{}, {a}, {b, null}},
 		}),
 		`INSERT INTO hoge (a, b, c) VALUES (`+
-			`ARRAY[], `+
+			`ARRAY<TIMESTAMP>[], `+
 			`ARRAY[TIMESTAMP "2020-06-06T12:34:56.123456Z"], `+
 			`ARRAY[TIMESTAMP "2021-08-10T00:01:23.456789+09:00", NULL])`,
 	)
@@ -562,7 +562,7 @@ func TestInsertWithDateSliceSlice(t *testing.T) {
 			{{}, {a}, {b, c}},
 		}),
 		`INSERT INTO hoge (a, b, c) VALUES (`+
-			`ARRAY[], `+
+			`ARRAY<DATE>[], `+
 			`ARRAY[DATE "2024-03-02"], `+
 			`ARRAY[DATE "2025-06-20", DATE "2026-03-05"])`,
 	)
@@ -578,7 +578,7 @@ func TestInsertWithDatePtrSlice(t *testing.T) {
 		`INSERT INTO hoge (a, b, c) VALUES (`+
 			`DATE "2024-03-02", `+
 			`DATE "2025-06-20", `+
-			`NULL)`,
+			`CAST(NULL AS DATE))`,
 	)
 }
 
@@ -590,7 +590,7 @@ func TestInsertWithDatePtrSliceSlice(t *testing.T) {
 			{{}, {&a}, {&b, nil}},
 		}),
 		`INSERT INTO hoge (a, b, c) VALUES (`+
-			`ARRAY[], `+
+			`ARRAY<DATE>[], `+
 			`ARRAY[DATE "2024-03-02"], `+
 			`ARRAY[DATE "2025-06-20", NULL])`,
 	)
@@ -606,7 +606,7 @@ func TestInsertWithNullDateSlice(t *testing.T) {
 		`INSERT INTO hoge (a, b, c) VALUES (`+
 			`DATE "2024-03-02", `+
 			`DATE "2025-06-20", `+
-			`NULL)`,
+			`CAST(NULL AS DATE))`,
 	)
 }
 
@@ -619,7 +619,7 @@ func TestInsertWithNullDateSliceSlice(t *testing.T) {
 			{{}, {a}, {b, null}},
 		}),
 		`INSERT INTO hoge (a, b, c) VALUES (`+
-			`ARRAY[], `+
+			`ARRAY<DATE>[], `+
 			`ARRAY[DATE "2024-03-02"], `+
 			`ARRAY[DATE "2025-06-20", NULL])`,
 	)
@@ -712,6 +712,214 @@ func TestInsertWithGoStructWithTags(t *testing.T) {
 	)
 }
 
+type testInsertGoStructWithIgnoredFields struct {
+	ID      int64 `spanner:"UserID"`
+	Name    string
+	Ignored string `spanner:"-"`
+	private string
+}
+
+func TestInsertStruct(t *testing.T) {
+	testInsert(t,
+		memeduck.InsertStruct("hoge", []testInsertGoStructWithTags{
+			{A: "AAA", B: "BBB", C: "CCC"},
+		}),
+		`INSERT INTO hoge (ColumnA, ColumnB, C) VALUES ("AAA", "BBB", "CCC")`,
+	)
+	testInsert(t,
+		memeduck.InsertStruct("hoge", []*testInsertGoStructWithIgnoredFields{
+			{ID: 1, Name: "a", Ignored: "x", private: "y"},
+			{ID: 2, Name: "b"},
+		}),
+		`INSERT INTO hoge (UserID, Name) VALUES (1, "a"), (2, "b")`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", nil).Values([]testInsertGoStruct{
+			{A: "AAA", B: "BBB", C: "CCC"},
+		}),
+		`INSERT INTO hoge (A, B, C) VALUES ("AAA", "BBB", "CCC")`,
+	)
+}
+
+type testInsertGoStructWithRecursiveField struct {
+	ID     int64
+	Parent *testInsertGoStructWithRecursiveField
+}
+
+func TestInsertStructWithRecursiveField(t *testing.T) {
+	testInsert(t,
+		memeduck.InsertStruct("hoge", []testInsertGoStructWithRecursiveField{
+			{ID: 1},
+			{ID: 2, Parent: &testInsertGoStructWithRecursiveField{ID: 1}},
+		}),
+		`INSERT INTO hoge (ID, Parent) VALUES (1, NULL), (2, STRUCT(1, NULL))`,
+	)
+}
+
+func TestInsertStructWithInvalidArgs(t *testing.T) {
+	for _, rows := range []interface{}{
+		[]testInsertGoStruct{},
+		[][]int{{1}},
+		[]interface{}{testInsertGoStruct{}},
+		map[string]string{"a": "b"},
+		testInsertGoStruct{},
+		[]struct{ a int }{{1}},
+	} {
+		_, err := memeduck.InsertStruct("hoge", rows).SQL()
+		assert.Error(t, err, "%T", rows)
+	}
+}
+
+type testTimestamps struct {
+	CreatedAt int64 `spanner:"created_at"`
+	UpdatedAt int64 `spanner:"updated_at"`
+}
+
+type testAuditFields struct {
+	testTimestamps
+	UpdatedBy string `spanner:"updated_by"`
+}
+
+type testInsertGoStructWithEmbedded struct {
+	ID int64 `spanner:"id"`
+	*testAuditFields
+	UpdatedAt string `spanner:"updated_at"`
+}
+
+func TestInsertWithEmbeddedStruct(t *testing.T) {
+	testInsert(t,
+		memeduck.InsertStruct("hoge", []testInsertGoStructWithEmbedded{
+			{ID: 1, testAuditFields: &testAuditFields{testTimestamps{CreatedAt: 2, UpdatedAt: 3}, "foo"}, UpdatedAt: "bar"},
+			{ID: 4},
+		}),
+		`INSERT INTO hoge (id, created_at, updated_by, updated_at) VALUES (1, 2, "foo", "bar"), (4, CAST(NULL AS INT64), CAST(NULL AS STRING), "")`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"updated_by", "id"}).Values([]*testInsertGoStructWithEmbedded{
+			{ID: 1, testAuditFields: &testAuditFields{UpdatedBy: "foo"}},
+		}),
+		`INSERT INTO hoge (updated_by, id) VALUES ("foo", 1)`,
+	)
+}
+
+func TestInsertWithAmbiguousEmbeddedStruct(t *testing.T) {
+	type shadowed struct {
+		testTimestamps
+		Audit testAuditFields `spanner:"-"`
+		ID    int64           `spanner:"created_at"`
+	}
+	testInsert(t,
+		memeduck.InsertStruct("hoge", []shadowed{{ID: 1}}),
+		`INSERT INTO hoge (updated_at, created_at) VALUES (0, 1)`,
+	)
+	type created struct {
+		At int64 `spanner:"created_at"`
+	}
+	type ambiguous struct {
+		testTimestamps
+		*created
+	}
+	_, err := memeduck.InsertStruct("hoge", []ambiguous{{}}).SQL()
+	assert.Error(t, err)
+	_, err = memeduck.Insert("hoge", []string{"created_at"}).Values([]ambiguous{{}}).SQL()
+	assert.Error(t, err)
+}
+
+type testInsertGoStructWithOptions struct {
+	ID        int64     `spanner:"id"`
+	Name      string    `spanner:"name,default"`
+	FullName  string    `spanner:"full_name,generated"`
+	Version   int64     `spanner:"version,readonly"`
+	UpdatedAt time.Time `spanner:"updated_at,commit_ts"`
+}
+
+func TestInsertWithTagOptions(t *testing.T) {
+	testInsert(t,
+		memeduck.InsertStruct("hoge", []testInsertGoStructWithOptions{
+			{ID: 1, Name: "foo", FullName: "x", Version: 2, UpdatedAt: time.Unix(0, 0)},
+			{ID: 3},
+		}),
+		`INSERT INTO hoge (id, name, updated_at) VALUES (1, "foo", PENDING_COMMIT_TIMESTAMP()), (3, DEFAULT, PENDING_COMMIT_TIMESTAMP())`,
+	)
+	stmt, err := memeduck.InsertStruct("hoge", []testInsertGoStructWithOptions{{ID: 1}}).Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO hoge (id, name, updated_at) VALUES (@p1, DEFAULT, PENDING_COMMIT_TIMESTAMP())`, stmt.SQL)
+	assert.Equal(t, map[string]interface{}{"p1": int64(1)}, stmt.Params)
+
+	_, err = memeduck.Insert("hoge", []string{"id", "full_name"}).Values([]testInsertGoStructWithOptions{{}}).SQL()
+	assert.Error(t, err, "read-only column")
+}
+
+func TestInsertWithDefault(t *testing.T) {
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]interface{}{
+			{1, memeduck.Default},
+			{memeduck.Default, spanner.CommitTimestamp},
+		}),
+		`INSERT INTO hoge (a, b) VALUES (1, DEFAULT), (DEFAULT, PENDING_COMMIT_TIMESTAMP())`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([]map[string]interface{}{
+			{"a": memeduck.Default, "b": 1},
+		}),
+		`INSERT INTO hoge (a, b) VALUES (DEFAULT, 1)`,
+	)
+	type row struct {
+		A interface{} `spanner:"a"`
+		B time.Time   `spanner:"b"`
+	}
+	stmt, err := memeduck.InsertStruct("hoge", []row{{A: memeduck.Default, B: spanner.CommitTimestamp}}).Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO hoge (a, b) VALUES (DEFAULT, PENDING_COMMIT_TIMESTAMP())`, stmt.SQL)
+	assert.Empty(t, stmt.Params)
+}
+
+func TestInsertWithDefaultInUnsupportedPlace(t *testing.T) {
+	_, err := memeduck.Insert("hoge", []string{"a"}).Values([][][]interface{}{{{memeduck.Default}}}).SQL()
+	assert.Error(t, err, "DEFAULT in an array")
+	_, err = memeduck.Delete("hoge").Where(memeduck.Eq(memeduck.Ident("a"), memeduck.Default)).SQL()
+	assert.Error(t, err, "DEFAULT in WHERE")
+}
+
+func TestInsertWithOpaqueStruct(t *testing.T) {
+	type decimal struct {
+		coef int64
+		exp  int32
+	}
+	_, err := memeduck.Insert("hoge", []string{"a"}).Values([][]interface{}{{decimal{1, 2}}}).SQL()
+	assert.Error(t, err)
+}
+
+func TestInsertWithMap(t *testing.T) {
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([]map[string]interface{}{
+			{"b": "BBB", "a": int64(1)},
+			{"a": int64(2), "b": nil},
+		}),
+		`INSERT INTO hoge (a, b) VALUES (1, "BBB"), (2, NULL)`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([]interface{}{
+			map[string]string{"a": "AAA", "b": "BBB"},
+			[]string{"CCC", "DDD"},
+		}),
+		`INSERT INTO hoge (a, b) VALUES ("AAA", "BBB"), ("CCC", "DDD")`,
+	)
+}
+
+func TestInsertWithInvalidMap(t *testing.T) {
+	for _, row := range []interface{}{
+		map[string]interface{}{"a": 1},
+		map[string]interface{}{"a": 1, "b": 2, "c": 3},
+		map[string]interface{}{"A": 1, "b": 2},
+		map[int]interface{}{1: 1, 2: 2},
+		map[string]interface{}{"a": 1, "b": make(chan int)},
+	} {
+		_, err := memeduck.Insert("hoge", []string{"a", "b"}).Values([]interface{}{row}).SQL()
+		assert.Error(t, err, "%v", row)
+	}
+}
+
 func TestInsertWithHeteroSlice(t *testing.T) {
 	testInsert(t,
 		memeduck.Insert("hoge", []string{"a", "b", "c", "d"}).Values([][]interface{}{
@@ -739,3 +947,142 @@ func parseDate(t *testing.T, s string) civil.Date {
 	assert.Nil(t, err, "failed to parse %s", s)
 	return d
 }
+
+func TestInsertWithUntyped(t *testing.T) {
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b", "c"}).Values([][]interface{}{
+			{(*string)(nil), []int64{}, spanner.NullDate{}},
+		}).Untyped(),
+		`INSERT INTO hoge (a, b, c) VALUES (NULL, ARRAY[], NULL)`,
+	)
+}
+
+func TestInsertWithSelect(t *testing.T) {
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).
+			Select(memeduck.Select("fuga", []string{"c", "d"}).Where(memeduck.Eq(memeduck.Ident("e"), 1))),
+		`INSERT INTO hoge (a, b) SELECT c, d FROM fuga WHERE e = 1`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).
+			Select(memeduck.Select("fuga", []string{"c"}).SubQuery(memeduck.ScalarSubQuery(memeduck.Select("piyo", []string{"d"})))),
+		`INSERT INTO hoge (a, b) SELECT c, (SELECT d FROM piyo) FROM fuga`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a"}).
+			Values([][]int{{1}}).
+			Select(memeduck.Select("fuga", []string{"c"})),
+		`INSERT INTO hoge (a) SELECT c FROM fuga`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a"}).
+			Select(memeduck.Select("fuga", []string{"c"})).
+			Values([][]int{{1}}),
+		`INSERT INTO hoge (a) VALUES (1)`,
+	)
+}
+
+func TestInsertWithSelectWithWrongNumberOfColumns(t *testing.T) {
+	_, err := memeduck.Insert("hoge", []string{"a", "b"}).
+		Select(memeduck.Select("fuga", []string{"c"})).
+		SQL()
+	assert.Error(t, err, "too few columns")
+	_, err = memeduck.Insert("hoge", []string{"a"}).
+		Select(memeduck.Select("fuga", []string{"c", "d"})).
+		SQL()
+	assert.Error(t, err, "too many columns")
+	_, err = memeduck.Insert("hoge", []string{"a"}).
+		Select(memeduck.Select("fuga", []string{"c", "d"}).AsStruct()).
+		SQL()
+	assert.Error(t, err, "AS STRUCT")
+	_, err = memeduck.Insert("hoge", []string{"a"}).
+		Select(memeduck.Select("fuga", []string{"c"}).AsStruct()).
+		SQL()
+	assert.Error(t, err, "AS STRUCT with a single column")
+}
+
+func TestInsertWithSelectStatement(t *testing.T) {
+	stmt, err := memeduck.Insert("hoge", []string{"a", "b"}).
+		Select(memeduck.Select("fuga", []string{"c", "d"}).
+			Where(memeduck.Eq(memeduck.Ident("e"), memeduck.Param("e")), memeduck.Eq(memeduck.Ident("f"), "x")).
+			Params(map[string]interface{}{"e": 1})).
+		Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO hoge (a, b) SELECT c, d FROM fuga WHERE e = @e AND f = @p1`, stmt.SQL)
+	assert.Equal(t, map[string]interface{}{"e": 1, "p1": "x"}, stmt.Params)
+}
+
+func TestInsertOrUpdate(t *testing.T) {
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}}).OrUpdate(),
+		`INSERT OR UPDATE INTO hoge (a, b) VALUES (1, 2)`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}}).OrIgnore().OrUpdate(),
+		`INSERT OR UPDATE INTO hoge (a, b) VALUES (1, 2)`,
+	)
+}
+
+func TestInsertOrIgnore(t *testing.T) {
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}}).OrIgnore(),
+		`INSERT OR IGNORE INTO hoge (a, b) VALUES (1, 2)`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a"}).Select(memeduck.Select("fuga", []string{"b"})).OrUpdate().OrIgnore(),
+		`INSERT OR IGNORE INTO hoge (a) SELECT b FROM fuga`,
+	)
+}
+
+func TestInsertOrUpdateStatement(t *testing.T) {
+	stmt, err := memeduck.Insert("hoge", []string{"a", "b"}).Values([][]interface{}{{1, "x"}}).OrUpdate().Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT OR UPDATE INTO hoge (a, b) VALUES (@p1, @p2)`, stmt.SQL)
+	assert.Equal(t, map[string]interface{}{"p1": int64(1), "p2": "x"}, stmt.Params)
+}
+
+func TestInsertWithThenReturn(t *testing.T) {
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}}).ThenReturn("id", "a"),
+		`INSERT INTO hoge (a, b) VALUES (1, 2) THEN RETURN id, a`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}}).ThenReturn("*"),
+		`INSERT INTO hoge (a, b) VALUES (1, 2) THEN RETURN *`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}}).OrUpdate().ThenReturnWithAction("", "*"),
+		`INSERT OR UPDATE INTO hoge (a, b) VALUES (1, 2) THEN RETURN WITH ACTION *`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a", "b"}).Values([][]int{{1, 2}}).
+			ThenReturn("a").
+			ThenReturnWithAction("act", memeduck.Ident("t", "a"), "b"),
+		`INSERT INTO hoge (a, b) VALUES (1, 2) THEN RETURN WITH ACTION AS act t.a, b`,
+	)
+	testInsert(t,
+		memeduck.Insert("hoge", []string{"a"}).Values([][]int{{1}}).
+			ThenReturn("a", memeduck.ScalarSubQuery(memeduck.Select("fuga", []string{"b"})).As("b")),
+		`INSERT INTO hoge (a) VALUES (1) THEN RETURN a, (SELECT b FROM fuga) AS b`,
+	)
+}
+
+func TestInsertWithEmptyThenReturn(t *testing.T) {
+	_, err := memeduck.Insert("hoge", []string{"a"}).Values([][]int{{1}}).ThenReturn().SQL()
+	assert.Error(t, err, "empty THEN RETURN")
+	_, err = memeduck.Insert("hoge", []string{"a"}).Values([][]int{{1}}).ThenReturnWithAction("act").SQL()
+	assert.Error(t, err, "empty THEN RETURN WITH ACTION")
+}
+
+func TestInsertWithThenReturnStatement(t *testing.T) {
+	stmt, err := memeduck.Insert("hoge", []string{"a"}).Values([][]int{{1}}).
+		ThenReturn("a", memeduck.ScalarSubQuery(
+			memeduck.Select("fuga", []string{"b"}).
+				Where(memeduck.Eq(memeduck.Ident("c"), memeduck.Param("c"))).
+				Params(map[string]interface{}{"c": "x"}),
+		)).
+		Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO hoge (a) VALUES (@p1) THEN RETURN a, (SELECT b FROM fuga WHERE c = @c)`, stmt.SQL)
+	assert.Equal(t, map[string]interface{}{"p1": int64(1), "c": "x"}, stmt.Params)
+}
@@ -1,8 +1,11 @@
 package internal
 
 import (
+	"math"
+	"math/big"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/civil"
@@ -11,12 +14,40 @@ import (
 	"github.com/pkg/errors"
 )
 
+// Float32TypeName is the name of FLOAT32 type, which is not defined in memefish.
+const Float32TypeName ast.ScalarTypeName = "FLOAT32"
+
 // ASTExpr is a type that can be converted into ast.Expr.
 type ASTExpr interface {
 	ToASTExpr() (ast.Expr, error)
 }
 
+// ToExpr converts val into SQL expression.
+// If val is NULL and its SQL type can be determined from its Go type, it returns a typed NULL like `CAST(NULL AS STRING)`.
 func ToExpr(val interface{}) (ast.Expr, error) {
+	expr, err := toExpr(val)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := expr.(*ast.NullLiteral); ok && val != nil {
+		if typ, ok := TypeOf(reflect.TypeOf(val)); ok {
+			return TypedNullLit(typ), nil
+		}
+	}
+	return expr, nil
+}
+
+// IsNull reports whether val is converted into NULL by ToExpr.
+func IsNull(val interface{}) bool {
+	expr, err := toExpr(val)
+	if err != nil {
+		return false
+	}
+	_, ok := expr.(*ast.NullLiteral)
+	return ok
+}
+
+func toExpr(val interface{}) (ast.Expr, error) {
 	switch v := val.(type) {
 	case nil:
 		return NullLit(), nil
@@ -81,17 +112,17 @@ func ToExpr(val interface{}) (ast.Expr, error) {
 		}
 		return FloatLit(v.Float64), nil
 	case time.Time:
-		return TimeLit(v), nil
+		return timeToExpr(v), nil
 	case *time.Time:
 		if v == nil {
 			return NullLit(), nil
 		}
-		return TimeLit(*v), nil
+		return timeToExpr(*v), nil
 	case spanner.NullTime:
 		if !v.Valid {
 			return NullLit(), nil
 		}
-		return TimeLit(v.Time), nil
+		return timeToExpr(v.Time), nil
 	case civil.Date:
 		return DateLit(v), nil
 	case *civil.Date:
@@ -104,28 +135,116 @@ func ToExpr(val interface{}) (ast.Expr, error) {
 			return NullLit(), nil
 		}
 		return DateLit(v.Date), nil
+	case big.Rat:
+		return NumericLit(&v)
+	case *big.Rat:
+		if v == nil {
+			return NullLit(), nil
+		}
+		return NumericLit(v)
+	case spanner.NullNumeric:
+		if !v.Valid {
+			return NullLit(), nil
+		}
+		return NumericLit(&v.Numeric)
 	default:
 		if se, ok := val.(ASTExpr); ok {
 			return se.ToASTExpr()
 		}
-		// TODO: support big.Rat
-		// Slices
 		valV := reflect.ValueOf(val)
-		if valV.Type().Kind() == reflect.Slice {
-			exprs := make([]ast.Expr, 0, valV.Len())
-			for i := 0; i < valV.Len(); i++ {
-				vi := valV.Index(i).Interface()
-				ei, err := ToExpr(vi)
-				if err != nil {
-					return nil, errors.WithMessagef(err, "at index %d", i)
-				}
-				exprs = append(exprs, ei)
+		if valV.Kind() == reflect.Ptr && valV.IsNil() {
+			return NullLit(), nil
+		}
+		if conv, ok := lookupConverter(valV.Type()); ok {
+			converted, err := conv(val)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "can't convert %T", val)
 			}
-			return ArrayLit(exprs), nil
-		} else {
-			return nil, errors.Errorf("can't convert %T into SQL expr", val)
+			return ToExpr(converted)
+		}
+		if enc, ok := asEncoder(valV); ok {
+			encoded, err := enc.EncodeSpanner()
+			if err != nil {
+				return nil, errors.WithMessagef(err, "can't encode %T", val)
+			}
+			return ToExpr(encoded)
+		}
+		return reflectToExpr(valV)
+	}
+}
 
+// asEncoder returns valV as spanner.Encoder.
+// If only the pointer type of valV implements spanner.Encoder, it returns a pointer to a copy of valV.
+func asEncoder(valV reflect.Value) (spanner.Encoder, bool) {
+	if enc, ok := valV.Interface().(spanner.Encoder); ok {
+		return enc, true
+	}
+	if !implementsEncoder(valV.Type()) {
+		return nil, false
+	}
+	p := reflect.New(valV.Type())
+	p.Elem().Set(valV)
+	return p.Interface().(spanner.Encoder), true
+}
+
+// reflectToExpr converts values whose types are not known to ToExpr by looking at their underlying kinds.
+// This makes it possible to use named types like `type UserID int64` as well as other sized numeric types.
+func reflectToExpr(valV reflect.Value) (ast.Expr, error) {
+	switch valV.Kind() {
+	case reflect.Ptr:
+		if valV.IsNil() {
+			return NullLit(), nil
+		}
+		return ToExpr(valV.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return IntLit(valV.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := valV.Uint()
+		if v > math.MaxInt64 {
+			return nil, errors.Errorf("%d (%s) overflows INT64", v, valV.Type().String())
+		}
+		return IntLit(int64(v)), nil
+	case reflect.Float32:
+		return Float32Lit(float32(valV.Float())), nil
+	case reflect.Float64:
+		return FloatLit(valV.Float()), nil
+	case reflect.String:
+		return StringLit(valV.String()), nil
+	case reflect.Bool:
+		return BoolLit(valV.Bool()), nil
+	case reflect.Slice:
+		if valV.Type().Elem().Kind() == reflect.Uint8 {
+			if valV.IsNil() {
+				return NullLit(), nil
+			}
+			return BytesLit(valV.Bytes()), nil
+		}
+		exprs := make([]ast.Expr, 0, valV.Len())
+		allNull := true
+		for i := 0; i < valV.Len(); i++ {
+			vi := valV.Index(i).Interface()
+			ei, err := ToExpr(vi)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "at index %d", i)
+			}
+			// Elements don't need to be typed because the type of the array is determined by other elements or the array itself.
+			ei = UntypeExpr(ei)
+			if _, ok := ei.(*ast.NullLiteral); !ok {
+				allNull = false
+			}
+			exprs = append(exprs, ei)
+		}
+		lit := ArrayLit(exprs)
+		if allNull {
+			if typ, ok := TypeOf(valV.Type().Elem()); ok {
+				lit.Type = typ
+			}
 		}
+		return lit, nil
+	case reflect.Struct:
+		return structToExpr(valV)
+	default:
+		return nil, errors.Errorf("can't convert %s into SQL expr", valV.Type().String())
 	}
 }
 
@@ -160,6 +279,28 @@ func FloatLit(v float64) *ast.FloatLiteral {
 	}
 }
 
+// Float32Lit creates a FLOAT32 value.
+// Since there is no FLOAT32 literal in Spanner SQL, it is represented as `CAST(v AS FLOAT32)`.
+func Float32Lit(v float32) *ast.CastExpr {
+	return &ast.CastExpr{
+		Expr: &ast.FloatLiteral{
+			Value: strconv.FormatFloat(float64(v), 'e', -1, 32),
+		},
+		Type: &ast.SimpleType{
+			Name: Float32TypeName,
+		},
+	}
+}
+
+// timeToExpr converts v into TIMESTAMP literal, or `PENDING_COMMIT_TIMESTAMP()` if v is spanner.CommitTimestamp.
+func timeToExpr(v time.Time) ast.Expr {
+	// spanner.CommitTimestamp is distinguished from other times by its location, which == compares.
+	if v == spanner.CommitTimestamp {
+		return PendingCommitTimestamp()
+	}
+	return TimeLit(v)
+}
+
 func TimeLit(v time.Time) *ast.TimestampLiteral {
 	return &ast.TimestampLiteral{
 		Value: &ast.StringLiteral{
@@ -176,6 +317,36 @@ func DateLit(v civil.Date) *ast.DateLiteral {
 	}
 }
 
+// NumericLit creates a NUMERIC literal.
+// It fails if v can't be represented as a NUMERIC value without loss of precision.
+func NumericLit(v *big.Rat) (*ast.NumericLiteral, error) {
+	str, err := numericString(v)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.NumericLiteral{
+		Value: &ast.StringLiteral{
+			Value: str,
+		},
+	}, nil
+}
+
+var numericScale = new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(spanner.NumericScaleDigits), nil))
+
+func numericString(v *big.Rat) (string, error) {
+	if !new(big.Rat).Mul(v, numericScale).IsInt() {
+		return "", errors.Errorf("NUMERIC can't have more than %d digits after the decimal point: %s", spanner.NumericScaleDigits, v.RatString())
+	}
+	str := v.FloatString(spanner.NumericScaleDigits)
+	str = strings.TrimRight(str, "0")
+	str = strings.TrimSuffix(str, ".")
+	whole := strings.TrimLeft(strings.SplitN(str, ".", 2)[0], "-")
+	if len(whole) > spanner.NumericPrecisionDigits-spanner.NumericScaleDigits {
+		return "", errors.Errorf("NUMERIC can't have more than %d digits before the decimal point: %s", spanner.NumericPrecisionDigits-spanner.NumericScaleDigits, str)
+	}
+	return str, nil
+}
+
 func ArrayLit(exprs []ast.Expr) *ast.ArrayLiteral {
 	return &ast.ArrayLiteral{
 		Values: exprs,
@@ -185,3 +356,47 @@ func ArrayLit(exprs []ast.Expr) *ast.ArrayLiteral {
 func NullLit() *ast.NullLiteral {
 	return &ast.NullLiteral{}
 }
+
+// PendingCommitTimestamp creates `PENDING_COMMIT_TIMESTAMP()`.
+func PendingCommitTimestamp() *ast.CallExpr {
+	return &ast.CallExpr{
+		Func: &ast.Ident{Name: "PENDING_COMMIT_TIMESTAMP"},
+	}
+}
+
+// TypedNullLit creates `CAST(NULL AS typ)`.
+func TypedNullLit(typ ast.Type) *ast.CastExpr {
+	return &ast.CastExpr{
+		Expr: NullLit(),
+		Type: typ,
+	}
+}
+
+// UntypeExpr converts typed NULLs and typed arrays that consist only of NULLs into untyped ones.
+// Other expressions are returned as is.
+func UntypeExpr(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.CastExpr:
+		if _, ok := e.Expr.(*ast.NullLiteral); ok {
+			return NullLit()
+		}
+	case *ast.ArrayLiteral:
+		if e.Type == nil {
+			return e
+		}
+		for _, v := range e.Values {
+			if _, ok := v.(*ast.NullLiteral); !ok {
+				return e
+			}
+		}
+		return ArrayLit(e.Values)
+	}
+	return expr
+}
+
+// Untype converts all typed NULLs and typed arrays in node into untyped ones.
+func Untype(node ast.Node) error {
+	return Rewrite(node, func(expr ast.Expr) (ast.Expr, error) {
+		return UntypeExpr(expr), nil
+	})
+}
@@ -2,6 +2,9 @@ package internal_test
 
 import (
 	"errors"
+	"math"
+	"math/big"
+	"reflect"
 	"testing"
 	"time"
 
@@ -20,6 +23,10 @@ func testAST(t *testing.T, val interface{}, expected ast.Expr) {
 	assert.Equal(t, expected, actual)
 }
 
+func typedNull(name ast.ScalarTypeName) ast.Expr {
+	return internal.TypedNullLit(&ast.SimpleType{Name: name})
+}
+
 func TestASTWithNil(t *testing.T) {
 	testAST(t, nil, internal.NullLit())
 }
@@ -31,12 +38,12 @@ func TestASTWithString(t *testing.T) {
 func TestASTWithStringPtr(t *testing.T) {
 	var v = "hoge"
 	testAST(t, &v, internal.StringLit("hoge"))
-	testAST(t, (*string)(nil), internal.NullLit())
+	testAST(t, (*string)(nil), typedNull(ast.StringTypeName))
 }
 
 func TestASTWithNullString(t *testing.T) {
 	testAST(t, spanner.NullString{StringVal: "hoge", Valid: true}, internal.StringLit("hoge"))
-	testAST(t, spanner.NullString{}, internal.NullLit())
+	testAST(t, spanner.NullString{}, typedNull(ast.StringTypeName))
 }
 
 func TestASTWithBytes(t *testing.T) {
@@ -50,7 +57,7 @@ func TestASTWithInt(t *testing.T) {
 func TestASTWithIntPtr(t *testing.T) {
 	var v int = 123
 	testAST(t, &v, internal.IntLit(123))
-	testAST(t, (*int)(nil), internal.NullLit())
+	testAST(t, (*int)(nil), typedNull(ast.Int64TypeName))
 }
 
 func TestASTWithInt64(t *testing.T) {
@@ -60,12 +67,60 @@ func TestASTWithInt64(t *testing.T) {
 func TestASTWithInt64Ptr(t *testing.T) {
 	var v int64 = 123
 	testAST(t, &v, internal.IntLit(123))
-	testAST(t, (*int64)(nil), internal.NullLit())
+	testAST(t, (*int64)(nil), typedNull(ast.Int64TypeName))
 }
 
 func TestASTWithNullInt64(t *testing.T) {
 	testAST(t, spanner.NullInt64{Int64: 123, Valid: true}, internal.IntLit(123))
-	testAST(t, spanner.NullInt64{}, internal.NullLit())
+	testAST(t, spanner.NullInt64{}, typedNull(ast.Int64TypeName))
+}
+
+func TestASTWithSizedInts(t *testing.T) {
+	testAST(t, int8(-12), internal.IntLit(-12))
+	testAST(t, int16(-123), internal.IntLit(-123))
+	testAST(t, int32(-1234), internal.IntLit(-1234))
+	testAST(t, uint(12), internal.IntLit(12))
+	testAST(t, uint8(12), internal.IntLit(12))
+	testAST(t, uint16(123), internal.IntLit(123))
+	testAST(t, uint32(1234), internal.IntLit(1234))
+	testAST(t, uint64(math.MaxInt64), internal.IntLit(math.MaxInt64))
+}
+
+func TestASTWithSizedIntPtrs(t *testing.T) {
+	var v int32 = 123
+	testAST(t, &v, internal.IntLit(123))
+	testAST(t, (*int32)(nil), typedNull(ast.Int64TypeName))
+	testAST(t, (*uint16)(nil), typedNull(ast.Int64TypeName))
+}
+
+func TestASTWithOverflowingUint64(t *testing.T) {
+	_, err := internal.ToExpr(uint64(math.MaxInt64 + 1))
+	assert.Error(t, err, "uint64 overflow")
+	_, err = internal.ToExpr([]uint64{1, math.MaxUint64})
+	assert.Error(t, err, "uint64 overflow in slice")
+}
+
+type testUserID int64
+type testUserName string
+type testFlag bool
+type testRatio float64
+type testBlob []byte
+
+func TestASTWithNamedTypes(t *testing.T) {
+	testAST(t, testUserID(123), internal.IntLit(123))
+	testAST(t, testUserName("hoge"), internal.StringLit("hoge"))
+	testAST(t, testFlag(true), internal.BoolLit(true))
+	testAST(t, testRatio(0.5), internal.FloatLit(0.5))
+	testAST(t, testBlob{0, 1}, internal.BytesLit([]byte{0, 1}))
+	testAST(t, testBlob(nil), typedNull(ast.BytesTypeName))
+
+	var id = testUserID(456)
+	testAST(t, &id, internal.IntLit(456))
+	testAST(t, (*testUserID)(nil), typedNull(ast.Int64TypeName))
+	testAST(t,
+		[]testUserID{1, 2},
+		internal.ArrayLit([]ast.Expr{internal.IntLit(1), internal.IntLit(2)}),
+	)
 }
 
 func TestASTWithBool(t *testing.T) {
@@ -76,12 +131,12 @@ func TestASTWithBool(t *testing.T) {
 func TestASTWithBoolPtr(t *testing.T) {
 	var v bool = true
 	testAST(t, &v, internal.BoolLit(true))
-	testAST(t, (*bool)(nil), internal.NullLit())
+	testAST(t, (*bool)(nil), typedNull(ast.BoolTypeName))
 }
 
 func TestASTWithNullBool(t *testing.T) {
 	testAST(t, spanner.NullBool{Bool: false, Valid: true}, internal.BoolLit(false))
-	testAST(t, spanner.NullBool{}, internal.NullLit())
+	testAST(t, spanner.NullBool{}, typedNull(ast.BoolTypeName))
 }
 
 func TestASTWithFloat64(t *testing.T) {
@@ -91,12 +146,23 @@ func TestASTWithFloat64(t *testing.T) {
 func TestASTWithFloat64Ptr(t *testing.T) {
 	var v float64 = 3.14
 	testAST(t, &v, internal.FloatLit(3.14))
-	testAST(t, (*float64)(nil), internal.NullLit())
+	testAST(t, (*float64)(nil), typedNull(ast.Float64TypeName))
 }
 
 func TestASTWithNullFloat64(t *testing.T) {
 	testAST(t, spanner.NullFloat64{Float64: 1.23, Valid: true}, internal.FloatLit(1.23))
-	testAST(t, spanner.NullFloat64{}, internal.NullLit())
+	testAST(t, spanner.NullFloat64{}, typedNull(ast.Float64TypeName))
+}
+
+func TestASTWithFloat32(t *testing.T) {
+	testAST(t, float32(3.14), internal.Float32Lit(3.14))
+	assert.Equal(t, `CAST(3.14e+00 AS FLOAT32)`, internal.Float32Lit(3.14).SQL())
+}
+
+func TestASTWithFloat32Ptr(t *testing.T) {
+	var v float32 = 3.14
+	testAST(t, &v, internal.Float32Lit(3.14))
+	testAST(t, (*float32)(nil), typedNull(internal.Float32TypeName))
 }
 
 func TestASTWithTime(t *testing.T) {
@@ -107,13 +173,20 @@ func TestASTWithTime(t *testing.T) {
 func TestASTWithTimePtr(t *testing.T) {
 	var v = time.Now()
 	testAST(t, &v, internal.TimeLit(v))
-	testAST(t, (*time.Time)(nil), internal.NullLit())
+	testAST(t, (*time.Time)(nil), typedNull(ast.TimestampTypeName))
 }
 
 func TestASTWithNullTime(t *testing.T) {
 	var v = time.Now()
 	testAST(t, spanner.NullTime{Time: v, Valid: true}, internal.TimeLit(v))
-	testAST(t, spanner.NullTime{}, internal.NullLit())
+	testAST(t, spanner.NullTime{}, typedNull(ast.TimestampTypeName))
+}
+
+func TestASTWithCommitTimestamp(t *testing.T) {
+	testAST(t, spanner.CommitTimestamp, internal.PendingCommitTimestamp())
+	testAST(t, &spanner.CommitTimestamp, internal.PendingCommitTimestamp())
+	testAST(t, spanner.NullTime{Time: spanner.CommitTimestamp, Valid: true}, internal.PendingCommitTimestamp())
+	testAST(t, time.Unix(0, 0), internal.TimeLit(time.Unix(0, 0)))
 }
 
 func TestASTWithDate(t *testing.T) {
@@ -126,14 +199,74 @@ func TestASTWithDatePtr(t *testing.T) {
 	v, err := civil.ParseDate("2021-05-22")
 	assert.Nil(t, err)
 	testAST(t, &v, internal.DateLit(v))
-	testAST(t, (*civil.Date)(nil), internal.NullLit())
+	testAST(t, (*civil.Date)(nil), typedNull(ast.DateTypeName))
 }
 
 func TestASTWithNullDate(t *testing.T) {
 	v, err := civil.ParseDate("2021-05-22")
 	assert.Nil(t, err)
 	testAST(t, spanner.NullDate{Date: v, Valid: true}, internal.DateLit(v))
-	testAST(t, spanner.NullDate{}, internal.NullLit())
+	testAST(t, spanner.NullDate{}, typedNull(ast.DateTypeName))
+}
+
+func numericLit(t *testing.T, v *big.Rat) ast.Expr {
+	lit, err := internal.NumericLit(v)
+	assert.Nil(t, err, "can't convert %s into NUMERIC", v.RatString())
+	return lit
+}
+
+func TestASTWithRat(t *testing.T) {
+	var v = big.NewRat(314, 100)
+	testAST(t, *v, numericLit(t, v))
+	testAST(t, *big.NewRat(-1, 4), numericLit(t, big.NewRat(-1, 4)))
+}
+
+func TestASTWithRatPtr(t *testing.T) {
+	var v = big.NewRat(314, 100)
+	testAST(t, v, numericLit(t, v))
+	testAST(t, (*big.Rat)(nil), typedNull(ast.NumericTypeName))
+}
+
+func TestASTWithNullNumeric(t *testing.T) {
+	var v = big.NewRat(314, 100)
+	testAST(t, spanner.NullNumeric{Numeric: *v, Valid: true}, numericLit(t, v))
+	testAST(t, spanner.NullNumeric{}, typedNull(ast.NumericTypeName))
+}
+
+func TestNumericLit(t *testing.T) {
+	testCases := []struct {
+		val      *big.Rat
+		expected string
+	}{
+		{big.NewRat(0, 1), `NUMERIC "0"`},
+		{big.NewRat(123, 1), `NUMERIC "123"`},
+		{big.NewRat(-314, 100), `NUMERIC "-3.14"`},
+		{big.NewRat(1, 1000000000), `NUMERIC "0.000000001"`},
+		{mustParseRat(t, "99999999999999999999999999999.999999999"), `NUMERIC "99999999999999999999999999999.999999999"`},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, numericLit(t, tc.val).SQL())
+	}
+}
+
+func TestNumericLitWithTooLargeScale(t *testing.T) {
+	_, err := internal.NumericLit(big.NewRat(1, 3))
+	assert.Error(t, err, "1/3")
+	_, err = internal.NumericLit(mustParseRat(t, "0.0000000001"))
+	assert.Error(t, err, "10 digits after the decimal point")
+}
+
+func TestNumericLitWithTooLargePrecision(t *testing.T) {
+	_, err := internal.NumericLit(mustParseRat(t, "100000000000000000000000000000"))
+	assert.Error(t, err, "30 digits before the decimal point")
+	_, err = internal.ToExpr(mustParseRat(t, "-100000000000000000000000000000"))
+	assert.Error(t, err, "30 digits before the decimal point")
+}
+
+func mustParseRat(t *testing.T, s string) *big.Rat {
+	v, ok := new(big.Rat).SetString(s)
+	assert.True(t, ok, "failed to parse %s", s)
+	return v
 }
 
 type customExpr struct{}
@@ -159,6 +292,13 @@ func TestASTWhenASTExprFails(t *testing.T) {
 	assert.ErrorIs(t, err, errToASTExprFailed)
 }
 
+func TestASTWithUnsupportedType(t *testing.T) {
+	_, err := internal.ToExpr(map[string]string{})
+	assert.Error(t, err, "map")
+	_, err = internal.ToExpr(make(chan int))
+	assert.Error(t, err, "chan")
+}
+
 func TestASTWithSlice(t *testing.T) {
 	testAST(t,
 		[]interface{}{nil, nil},
@@ -173,3 +313,127 @@ func TestASTWithSlice(t *testing.T) {
 		internal.ArrayLit([]ast.Expr{internal.IntLit(123), internal.StringLit("456")}),
 	)
 }
+
+func TestASTWithEmptySlice(t *testing.T) {
+	testAST(t, []string{}, &ast.ArrayLiteral{
+		Type:   &ast.SimpleType{Name: ast.StringTypeName},
+		Values: []ast.Expr{},
+	})
+	testAST(t, []testUserID{}, &ast.ArrayLiteral{
+		Type:   &ast.SimpleType{Name: ast.Int64TypeName},
+		Values: []ast.Expr{},
+	})
+	testAST(t, []interface{}{}, internal.ArrayLit([]ast.Expr{}))
+}
+
+func TestASTWithNullOnlySlice(t *testing.T) {
+	testAST(t, []*string{nil, nil}, &ast.ArrayLiteral{
+		Type:   &ast.SimpleType{Name: ast.StringTypeName},
+		Values: []ast.Expr{internal.NullLit(), internal.NullLit()},
+	})
+	var v = "hoge"
+	testAST(t,
+		[]*string{&v, nil},
+		internal.ArrayLit([]ast.Expr{internal.StringLit("hoge"), internal.NullLit()}),
+	)
+}
+
+func TestTypedSQL(t *testing.T) {
+	testCases := []struct {
+		val      interface{}
+		expected string
+	}{
+		{(*string)(nil), `CAST(NULL AS STRING)`},
+		{spanner.NullInt64{}, `CAST(NULL AS INT64)`},
+		{[]byte(nil), `CAST(NULL AS BYTES)`},
+		{[]int64{}, `ARRAY<INT64>[]`},
+		{[]spanner.NullDate{{}}, `ARRAY<DATE>[NULL]`},
+		{[]*big.Rat{nil}, `ARRAY<NUMERIC>[NULL]`},
+		{[][]byte{}, `ARRAY<BYTES>[]`},
+		{[]float32{}, `ARRAY<FLOAT32>[]`},
+		{[]interface{}{nil}, `ARRAY[NULL]`},
+		{[]*customExpr{}, `ARRAY[]`},
+	}
+	for _, tc := range testCases {
+		expr, err := internal.ToExpr(tc.val)
+		assert.Nil(t, err, tc.expected)
+		assert.Equal(t, tc.expected, expr.SQL())
+	}
+}
+
+func TestUntypeExpr(t *testing.T) {
+	testCases := []struct {
+		val      interface{}
+		expected string
+	}{
+		{(*string)(nil), `NULL`},
+		{[]int64{}, `ARRAY[]`},
+		{[]spanner.NullDate{{}}, `ARRAY[NULL]`},
+		{[]int64{1}, `ARRAY[1]`},
+		{float32(1), `CAST(1e+00 AS FLOAT32)`},
+	}
+	for _, tc := range testCases {
+		expr, err := internal.ToExpr(tc.val)
+		assert.Nil(t, err, tc.expected)
+		assert.Equal(t, tc.expected, internal.UntypeExpr(expr).SQL())
+	}
+}
+
+type testEncoder struct {
+	val string
+}
+
+func (e *testEncoder) EncodeSpanner() (interface{}, error) {
+	return "encoded " + e.val, nil
+}
+
+type testEnum int
+
+func (e testEnum) EncodeSpanner() (interface{}, error) {
+	if e == 0 {
+		return nil, errToASTExprFailed
+	}
+	return []string{"", "FOO", "BAR"}[e], nil
+}
+
+type testStructWithEncoderField struct {
+	A testEncoder
+	B *testEncoder
+}
+
+func TestASTWithEncoderField(t *testing.T) {
+	testAST(t,
+		testStructWithEncoderField{A: testEncoder{val: "a"}, B: &testEncoder{val: "b"}},
+		&ast.StructLiteral{Values: []ast.Expr{internal.StringLit("encoded a"), internal.StringLit("encoded b")}},
+	)
+	_, ok := internal.TypeOf(reflect.TypeOf(testEncoder{}))
+	assert.False(t, ok)
+}
+
+func TestASTWithEncoder(t *testing.T) {
+	testAST(t, &testEncoder{val: "hoge"}, internal.StringLit("encoded hoge"))
+	testAST(t, (*testEncoder)(nil), internal.NullLit())
+	testAST(t, testEncoder{val: "hoge"}, internal.StringLit("encoded hoge"))
+	testAST(t, testEnum(1), internal.StringLit("FOO"))
+	testAST(t,
+		[]testEnum{1, 2},
+		internal.ArrayLit([]ast.Expr{internal.StringLit("FOO"), internal.StringLit("BAR")}),
+	)
+	testAST(t, []testEnum{}, internal.ArrayLit([]ast.Expr{}))
+}
+
+func TestASTWhenEncoderFails(t *testing.T) {
+	_, err := internal.ToExpr(testEnum(0))
+	assert.ErrorIs(t, err, errToASTExprFailed)
+}
+
+func TestIsNull(t *testing.T) {
+	assert.True(t, internal.IsNull(nil))
+	assert.True(t, internal.IsNull((*string)(nil)))
+	assert.True(t, internal.IsNull(spanner.NullString{}))
+	assert.True(t, internal.IsNull((*testEncoder)(nil)))
+	assert.False(t, internal.IsNull(""))
+	assert.False(t, internal.IsNull(0))
+	assert.False(t, internal.IsNull([]int64{}))
+	assert.False(t, internal.IsNull(testEnum(0)))
+}
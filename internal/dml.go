@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/MakeNowJust/memefish/pkg/token"
+)
+
+// InsertMode specifies how INSERT statements treat rows that already exist.
+type InsertMode string
+
+const (
+	InsertModeDefault InsertMode = ""
+	InsertModeUpdate  InsertMode = "UPDATE"
+	InsertModeIgnore  InsertMode = "IGNORE"
+)
+
+// DML is a DML statement with clauses that are not supported by memefish.
+// Items of UPDATE statements whose Expr is nil are rendered as `path = DEFAULT`.
+type DML struct {
+	Stmt       ast.Node
+	InsertMode InsertMode
+	ThenReturn *ThenReturn
+}
+
+// ThenReturn is a THEN RETURN clause.
+type ThenReturn struct {
+	WithAction  bool
+	ActionAlias string
+	Items       []ast.SelectItem
+}
+
+func (r *ThenReturn) SQL() string {
+	sql := "THEN RETURN "
+	if r.WithAction {
+		sql += "WITH ACTION "
+		if r.ActionAlias != "" {
+			sql += "AS " + (&ast.Ident{Name: r.ActionAlias}).SQL() + " "
+		}
+	}
+	for i, item := range r.Items {
+		if i != 0 {
+			sql += ", "
+		}
+		sql += item.SQL()
+	}
+	return sql
+}
+
+func (d *DML) Pos() token.Pos {
+	return d.Stmt.Pos()
+}
+
+func (d *DML) End() token.Pos {
+	return d.Stmt.End()
+}
+
+func (d *DML) SQL() string {
+	var sql string
+	if u, ok := d.Stmt.(*ast.Update); ok {
+		sql = updateSQL(u)
+	} else {
+		sql = d.Stmt.SQL()
+	}
+	if d.InsertMode != InsertModeDefault {
+		sql = "INSERT OR " + string(d.InsertMode) + " INTO " + strings.TrimPrefix(sql, "INSERT INTO ")
+	}
+	if d.ThenReturn != nil {
+		sql += " " + d.ThenReturn.SQL()
+	}
+	return sql
+}
+
+// updateSQL is the same as (*ast.Update).SQL except that it supports `SET path = DEFAULT`.
+func updateSQL(u *ast.Update) string {
+	sql := "UPDATE " + u.TableName.SQL()
+	if u.As != nil {
+		sql += " " + u.As.SQL()
+	}
+	for i, item := range u.Updates {
+		if i == 0 {
+			sql += " SET "
+		} else {
+			sql += ", "
+		}
+		if item.Expr != nil {
+			sql += item.SQL()
+			continue
+		}
+		for j, id := range item.Path {
+			if j != 0 {
+				sql += "."
+			}
+			sql += id.SQL()
+		}
+		sql += " = DEFAULT"
+	}
+	sql += " " + u.Where.SQL()
+	return sql
+}
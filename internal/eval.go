@@ -0,0 +1,512 @@
+package internal
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/pkg/errors"
+)
+
+// Evaluator evaluates SQL expressions in memory.
+//
+// Values are represented as one of nil (NULL), bool, int64, float64, *big.Rat, string, []byte, time.Time, civil.Date,
+// or []interface{} (ARRAY), and BOOL expressions follow the three-valued logic of Spanner.
+type Evaluator struct {
+	// Column returns the value of the given column.
+	// The value is converted into SQL expression by ToExpr and then evaluated.
+	Column func(name string) (interface{}, error)
+	// Params are values of query parameters.
+	Params map[string]interface{}
+}
+
+// EvalBool evaluates expr and returns its truth value. NULL is treated as FALSE as in WHERE clauses.
+func (e *Evaluator) EvalBool(expr ast.Expr) (bool, error) {
+	v, err := e.evalBool(expr)
+	if err != nil {
+		return false, err
+	}
+	return v != nil && v.(bool), nil
+}
+
+// Eval evaluates expr and returns its value.
+func (e *Evaluator) Eval(expr ast.Expr) (interface{}, error) {
+	switch x := expr.(type) {
+	case *ast.NullLiteral:
+		return nil, nil
+	case *ast.BoolLiteral:
+		return x.Value, nil
+	case *ast.ArrayLiteral:
+		values := make([]interface{}, 0, len(x.Values))
+		for _, v := range x.Values {
+			val, err := e.Eval(v)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, val)
+		}
+		return values, nil
+	case *ast.CastExpr:
+		return e.evalCast(x)
+	case *ast.ParenExpr:
+		return e.Eval(x.Expr)
+	case *ast.Ident:
+		return e.column(x.Name)
+	case *ast.Path:
+		if len(x.Idents) != 1 {
+			return nil, errors.Errorf("can't evaluate %s", x.SQL())
+		}
+		return e.column(x.Idents[0].Name)
+	case *ast.Param:
+		v, ok := e.Params[x.Name]
+		if !ok {
+			return nil, errors.Errorf("query parameter @%s is not bound", x.Name)
+		}
+		return e.evalValue(v)
+	case *ast.BinaryExpr, *ast.UnaryExpr, *ast.IsNullExpr, *ast.IsBoolExpr, *ast.InExpr, *ast.BetweenExpr:
+		return e.evalBool(expr)
+	}
+	kind, ok := literalKindOf(expr)
+	if !ok {
+		return nil, errors.Errorf("can't evaluate %s", expr.SQL())
+	}
+	v, err := kind.decode(expr)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := v.(big.Rat); ok {
+		return &r, nil
+	}
+	return v, nil
+}
+
+func (e *Evaluator) column(name string) (interface{}, error) {
+	if e.Column == nil {
+		return nil, errors.Errorf("can't evaluate column %s", name)
+	}
+	v, err := e.Column(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.evalValue(v)
+}
+
+func (e *Evaluator) evalValue(v interface{}) (interface{}, error) {
+	expr, err := ToExpr(v)
+	if err != nil {
+		return nil, err
+	}
+	return e.Eval(expr)
+}
+
+func (e *Evaluator) evalCast(x *ast.CastExpr) (interface{}, error) {
+	v, err := e.Eval(x.Expr)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	if typ, ok := x.Type.(*ast.SimpleType); ok && typ.Name == Float32TypeName {
+		if f, ok := v.(float64); ok {
+			return f, nil
+		}
+	}
+	return nil, errors.Errorf("can't evaluate %s", x.SQL())
+}
+
+// evalBool evaluates expr as BOOL. It returns nil if the result is NULL.
+func (e *Evaluator) evalBool(expr ast.Expr) (interface{}, error) {
+	switch x := expr.(type) {
+	case *ast.BinaryExpr:
+		switch x.Op {
+		case ast.OpAnd, ast.OpOr:
+			return e.evalLogical(x)
+		case ast.OpLike, ast.OpNotLike:
+			return e.evalLike(x)
+		}
+		lhs, err := e.Eval(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := e.Eval(x.Right)
+		if err != nil {
+			return nil, err
+		}
+		return compareOp(x.Op, lhs, rhs)
+	case *ast.UnaryExpr:
+		if x.Op != ast.OpNot {
+			return nil, errors.Errorf("can't evaluate %s", x.SQL())
+		}
+		v, err := e.evalBool(x.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return not(v), nil
+	case *ast.IsNullExpr:
+		v, err := e.Eval(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		return (v == nil) != x.Not, nil
+	case *ast.IsBoolExpr:
+		v, err := e.evalBool(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		return (v == x.Right) != x.Not, nil
+	case *ast.InExpr:
+		v, err := e.evalIn(x)
+		if err != nil {
+			return nil, err
+		}
+		if x.Not {
+			return not(v), nil
+		}
+		return v, nil
+	case *ast.BetweenExpr:
+		v, err := e.evalBetween(x)
+		if err != nil {
+			return nil, err
+		}
+		if x.Not {
+			return not(v), nil
+		}
+		return v, nil
+	}
+	v, err := e.Eval(expr)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.(bool); v != nil && !ok {
+		return nil, errors.Errorf("%s is not BOOL", expr.SQL())
+	}
+	return v, nil
+}
+
+func (e *Evaluator) evalLogical(x *ast.BinaryExpr) (interface{}, error) {
+	lhs, err := e.evalBool(x.Left)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := e.evalBool(x.Right)
+	if err != nil {
+		return nil, err
+	}
+	// AND is FALSE if any operand is FALSE, and OR is TRUE if any operand is TRUE, regardless of NULLs.
+	dominant := x.Op == ast.OpOr
+	if lhs == dominant || rhs == dominant {
+		return dominant, nil
+	}
+	if lhs == nil || rhs == nil {
+		return nil, nil
+	}
+	return !dominant, nil
+}
+
+func (e *Evaluator) evalLike(x *ast.BinaryExpr) (interface{}, error) {
+	lhs, err := e.Eval(x.Left)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := e.Eval(x.Right)
+	if err != nil {
+		return nil, err
+	}
+	if lhs == nil || rhs == nil {
+		return nil, nil
+	}
+	var matched bool
+	switch l := lhs.(type) {
+	case string:
+		r, ok := rhs.(string)
+		if !ok {
+			return nil, errors.Errorf("can't apply LIKE to %T and %T", lhs, rhs)
+		}
+		matched, err = likeMatch([]rune(l), []rune(r))
+	case []byte:
+		r, ok := rhs.([]byte)
+		if !ok {
+			return nil, errors.Errorf("can't apply LIKE to %T and %T", lhs, rhs)
+		}
+		matched, err = likeMatch(bytesToRunes(l), bytesToRunes(r))
+	default:
+		return nil, errors.Errorf("can't apply LIKE to %T and %T", lhs, rhs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return matched != (x.Op == ast.OpNotLike), nil
+}
+
+func (e *Evaluator) evalIn(x *ast.InExpr) (interface{}, error) {
+	lhs, err := e.Eval(x.Left)
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	switch r := x.Right.(type) {
+	case *ast.UnnestInCondition:
+		v, err := e.Eval(r.Expr)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			arr, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("%s is not ARRAY", r.Expr.SQL())
+			}
+			values = arr
+		}
+	case *ast.ValuesInCondition:
+		for _, expr := range r.Exprs {
+			v, err := e.Eval(expr)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+	default:
+		return nil, errors.Errorf("can't evaluate %s", x.SQL())
+	}
+	// IN with an empty list is always FALSE even if the left hand side is NULL.
+	var result interface{} = false
+	for _, v := range values {
+		eq, err := compareOp(ast.OpEqual, lhs, v)
+		if err != nil {
+			return nil, err
+		}
+		if eq == true {
+			return true, nil
+		}
+		if eq == nil {
+			result = nil
+		}
+	}
+	return result, nil
+}
+
+func (e *Evaluator) evalBetween(x *ast.BetweenExpr) (interface{}, error) {
+	v, err := e.Eval(x.Left)
+	if err != nil {
+		return nil, err
+	}
+	min, err := e.Eval(x.RightStart)
+	if err != nil {
+		return nil, err
+	}
+	max, err := e.Eval(x.RightEnd)
+	if err != nil {
+		return nil, err
+	}
+	ge, err := compareOp(ast.OpGreaterEqual, v, min)
+	if err != nil {
+		return nil, err
+	}
+	le, err := compareOp(ast.OpLessEqual, v, max)
+	if err != nil {
+		return nil, err
+	}
+	if ge == false || le == false {
+		return false, nil
+	}
+	if ge == nil || le == nil {
+		return nil, nil
+	}
+	return true, nil
+}
+
+func not(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return !v.(bool)
+}
+
+func compareOp(op ast.BinaryOp, lhs, rhs interface{}) (interface{}, error) {
+	if lhs == nil || rhs == nil {
+		return nil, nil
+	}
+	c, err := compare(lhs, rhs)
+	if err != nil {
+		return nil, err
+	}
+	if isNaN(lhs) || isNaN(rhs) {
+		// NaN is neither equal to, less than nor greater than any value including NaN itself.
+		switch op {
+		case ast.OpEqual, ast.OpLess, ast.OpLessEqual, ast.OpGreater, ast.OpGreaterEqual:
+			return false, nil
+		case ast.OpNotEqual:
+			return true, nil
+		}
+	}
+	switch op {
+	case ast.OpEqual:
+		return c == 0, nil
+	case ast.OpNotEqual:
+		return c != 0, nil
+	case ast.OpLess:
+		return c < 0, nil
+	case ast.OpLessEqual:
+		return c <= 0, nil
+	case ast.OpGreater:
+		return c > 0, nil
+	case ast.OpGreaterEqual:
+		return c >= 0, nil
+	default:
+		return nil, errors.Errorf("can't evaluate operator %s", op)
+	}
+}
+
+// compare compares two non-NULL values. Numeric values of different types are promoted as in Spanner.
+func compare(lhs, rhs interface{}) (int, error) {
+	switch l := lhs.(type) {
+	case int64:
+		switch r := rhs.(type) {
+		case int64:
+			return compareInt64(l, r), nil
+		case float64:
+			return compareFloat64(float64(l), r), nil
+		case *big.Rat:
+			return new(big.Rat).SetInt64(l).Cmp(r), nil
+		}
+	case float64:
+		switch r := rhs.(type) {
+		case int64:
+			return compareFloat64(l, float64(r)), nil
+		case float64:
+			return compareFloat64(l, r), nil
+		case *big.Rat:
+			f, _ := r.Float64()
+			return compareFloat64(l, f), nil
+		}
+	case *big.Rat:
+		switch r := rhs.(type) {
+		case int64:
+			return l.Cmp(new(big.Rat).SetInt64(r)), nil
+		case float64:
+			f, _ := l.Float64()
+			return compareFloat64(f, r), nil
+		case *big.Rat:
+			return l.Cmp(r), nil
+		}
+	case bool:
+		if r, ok := rhs.(bool); ok {
+			return compareInt64(boolToInt64(l), boolToInt64(r)), nil
+		}
+	case string:
+		if r, ok := rhs.(string); ok {
+			return compareString(l, r), nil
+		}
+	case []byte:
+		if r, ok := rhs.([]byte); ok {
+			return bytes.Compare(l, r), nil
+		}
+	case time.Time:
+		if r, ok := rhs.(time.Time); ok {
+			return compareTime(l, r), nil
+		}
+	case civil.Date:
+		if r, ok := rhs.(civil.Date); ok {
+			return compareTime(l.In(time.UTC), r.In(time.UTC)), nil
+		}
+	}
+	return 0, errors.Errorf("can't compare %T with %T", lhs, rhs)
+}
+
+func isNaN(v interface{}) bool {
+	f, ok := v.(float64)
+	return ok && math.IsNaN(f)
+}
+
+func compareInt64(l, r int64) int {
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func compareFloat64(l, r float64) int {
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func compareString(l, r string) int {
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func compareTime(l, r time.Time) int {
+	if l.Before(r) {
+		return -1
+	} else if l.After(r) {
+		return 1
+	}
+	return 0
+}
+
+func boolToInt64(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func bytesToRunes(b []byte) []rune {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return runes
+}
+
+// likeMatch reports whether s matches the LIKE pattern.
+// `%` matches any number of characters, `_` matches exactly one character, and `\` escapes the next character.
+func likeMatch(s, pattern []rune) (bool, error) {
+	type token struct {
+		r       rune
+		literal bool
+	}
+	tokens := make([]token, 0, len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' {
+			i++
+			if i >= len(pattern) {
+				return false, errors.New("LIKE pattern ends with a backslash")
+			}
+			tokens = append(tokens, token{r: pattern[i], literal: true})
+			continue
+		}
+		tokens = append(tokens, token{r: pattern[i]})
+	}
+	// matched[j] reports whether tokens[:i] matches s[:j].
+	matched := make([]bool, len(s)+1)
+	matched[0] = true
+	for _, t := range tokens {
+		next := make([]bool, len(s)+1)
+		for j := 0; j <= len(s); j++ {
+			switch {
+			case !t.literal && t.r == '%':
+				next[j] = matched[j] || (j > 0 && next[j-1])
+			case j == 0:
+				next[j] = false
+			case !t.literal && t.r == '_':
+				next[j] = matched[j-1]
+			default:
+				next[j] = matched[j-1] && s[j-1] == t.r
+			}
+		}
+		matched = next
+	}
+	return matched[len(s)], nil
+}
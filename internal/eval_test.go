@@ -0,0 +1,192 @@
+package internal_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/MakeNowJust/memefish/pkg/parser"
+	"github.com/MakeNowJust/memefish/pkg/token"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+func parseExpr(t *testing.T, src string) ast.Expr {
+	p := &parser.Parser{
+		Lexer: &parser.Lexer{
+			File: &token.File{Buffer: src},
+		},
+	}
+	expr, err := p.ParseExpr()
+	if err != nil {
+		t.Fatalf("can't parse %s: %v", src, err)
+	}
+	return expr
+}
+
+var testEvaluator = &internal.Evaluator{
+	Column: func(name string) (interface{}, error) {
+		switch name {
+		case "i":
+			return 1, nil
+		case "s":
+			return "hoge", nil
+		case "n":
+			return (*string)(nil), nil
+		case "ni":
+			return spanner.NullInt64{}, nil
+		case "f":
+			return float32(1.5), nil
+		case "arr":
+			return []int64{1, 2, 3}, nil
+		case "narr":
+			return []*int64{nil}, nil
+		case "t":
+			return time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), nil
+		case "d":
+			return civil.Date{Year: 2021, Month: 1, Day: 2}, nil
+		case "r":
+			return big.NewRat(3, 2), nil
+		case "nan":
+			return math.NaN(), nil
+		case "b":
+			return []byte("fuga"), nil
+		default:
+			return nil, errors.Errorf("unknown column %s", name)
+		}
+	},
+	Params: map[string]interface{}{
+		"p": 1,
+	},
+}
+
+func TestEvaluatorEval(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{`NULL`, nil},
+		{`1`, int64(1)},
+		{`"hoge"`, "hoge"},
+		{`i`, int64(1)},
+		{`n`, nil},
+		{`f`, 1.5},
+		{`r`, big.NewRat(3, 2)},
+		{`arr`, []interface{}{int64(1), int64(2), int64(3)}},
+		{`@p`, int64(1)},
+		{`CAST(NULL AS STRING)`, nil},
+	}
+	for _, tc := range testCases {
+		actual, err := testEvaluator.Eval(parseExpr(t, tc.expr))
+		assert.Nil(t, err, tc.expr)
+		assert.Equal(t, tc.expected, actual, tc.expr)
+	}
+}
+
+func TestEvaluatorEvalBool(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		expected bool
+	}{
+		{`TRUE`, true},
+		{`NULL`, false},
+		{`i = 1`, true},
+		{`i = @p`, true},
+		{`i != 1`, false},
+		{`i < 1.5`, true},
+		{`r > 1`, true},
+		{`r = NUMERIC "1.5"`, true},
+		{`f >= r`, true},
+		{`s = "hoge"`, true},
+		{`s > "abc"`, true},
+		{`b = B"fuga"`, true},
+		{`t = TIMESTAMP "2021-01-02T03:04:05Z"`, true},
+		{`t < TIMESTAMP "2021-01-02T03:04:05.1Z"`, true},
+		{`d = DATE "2021-01-02"`, true},
+		{`d > DATE "2021-01-01"`, true},
+		{`TRUE > FALSE`, true},
+		{`nan = nan`, false},
+		{`nan != nan`, true},
+		{`nan < 1`, false},
+		{`nan >= 1`, false},
+		{`r <= nan`, false},
+		{`nan IN UNNEST([nan])`, false},
+		{`nan BETWEEN nan AND nan`, false},
+		{`nan NOT BETWEEN 0 AND 1`, true},
+		{`n = "hoge"`, false},
+		{`n != "hoge"`, false},
+		{`NOT n = "hoge"`, false},
+		{`n IS NULL`, true},
+		{`ni IS NOT NULL`, false},
+		{`i IS NOT NULL`, true},
+		{`(n = "a") IS NULL`, true},
+		{`(i = 1) IS TRUE`, true},
+		{`(n = "a") IS NOT FALSE`, true},
+		{`i = 1 AND s = "hoge"`, true},
+		{`i = 1 AND n = "a"`, false},
+		{`NOT (i = 1 AND n = "a")`, false},
+		{`NOT (i = 2 AND n = "a")`, true},
+		{`i = 2 OR n = "a"`, false},
+		{`NOT (i = 2 OR n = "a")`, false},
+		{`i = 1 OR n = "a"`, true},
+		{`i IN UNNEST(arr)`, true},
+		{`i IN UNNEST(ARRAY<INT64>[])`, false},
+		{`n IN UNNEST(ARRAY<STRING>[])`, false},
+		{`NOT n IN UNNEST(ARRAY<STRING>[])`, true},
+		{`n NOT IN UNNEST(ARRAY<STRING>[])`, true},
+		{`n IN UNNEST(["a"])`, false},
+		{`n NOT IN UNNEST(["a"])`, false},
+		{`5 NOT IN UNNEST(arr)`, true},
+		{`5 NOT IN UNNEST(narr)`, false},
+		{`1 IN UNNEST([NULL, 1])`, true},
+		{`i IN (2, 1)`, true},
+		{`i BETWEEN 0 AND 2`, true},
+		{`i NOT BETWEEN 0 AND 2`, false},
+		{`i BETWEEN 0 AND NULL`, false},
+		{`i NOT BETWEEN 0 AND NULL`, false},
+		{`i NOT BETWEEN 2 AND NULL`, true},
+		{`s LIKE "h%"`, true},
+		{`s LIKE "%g_"`, true},
+		{`s LIKE "h_e"`, false},
+		{`s LIKE "%"`, true},
+		{`s NOT LIKE "f%"`, true},
+		{`"a%b" LIKE "a\\%b"`, true},
+		{`"axb" LIKE "a\\%b"`, false},
+		{`"a_b" LIKE "a\\_%"`, true},
+		{`"ほげ" LIKE "_げ"`, true},
+		{`n LIKE "%"`, false},
+		{`n NOT LIKE "%"`, false},
+		{`b LIKE B"f%a"`, true},
+	}
+	for _, tc := range testCases {
+		actual, err := testEvaluator.EvalBool(parseExpr(t, tc.expr))
+		assert.Nil(t, err, tc.expr)
+		assert.Equal(t, tc.expected, actual, tc.expr)
+	}
+}
+
+func TestEvaluatorWithInvalidExprs(t *testing.T) {
+	for _, expr := range []string{
+		`unknown = 1`,
+		`@unknown = 1`,
+		`i = "1"`,
+		`s LIKE 1`,
+		`1 LIKE 1`,
+		`s LIKE "\\"`,
+		`i IN UNNEST(i)`,
+		`i`,
+		`a.b = 1`,
+		`LENGTH(s) = 4`,
+		`-i = 1`,
+		`CAST(i AS STRING) = "1"`,
+	} {
+		_, err := testEvaluator.EvalBool(parseExpr(t, expr))
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,354 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/pkg/errors"
+)
+
+// MarshalLiteral converts a literal expression into a pair of its type name and JSON value.
+// The type name is empty if the type can't be determined, like untyped NULL.
+//
+// Values are represented as JSON values of the corresponding types, except that
+// INT64 and NUMERIC are represented as strings to avoid loss of precision,
+// BYTES is represented as a base64 string, and non-finite FLOAT64 values are represented as "NaN", "Infinity" and "-Infinity".
+func MarshalLiteral(expr ast.Expr) (string, json.RawMessage, error) {
+	switch e := expr.(type) {
+	case *ast.NullLiteral:
+		return "", json.RawMessage("null"), nil
+	case *ast.CastExpr:
+		if _, ok := e.Expr.(*ast.NullLiteral); ok {
+			name, err := jsonTypeName(e.Type)
+			if err != nil {
+				return "", nil, err
+			}
+			return name, json.RawMessage("null"), nil
+		}
+		if typ, ok := e.Type.(*ast.SimpleType); ok && typ.Name == Float32TypeName {
+			if _, ok := e.Expr.(*ast.FloatLiteral); ok {
+				value, err := marshalScalar(e.Expr)
+				if err != nil {
+					return "", nil, err
+				}
+				return string(Float32TypeName), value, nil
+			}
+		}
+	case *ast.BoolLiteral:
+		value, err := marshalScalar(e)
+		return string(ast.BoolTypeName), value, err
+	case *ast.ArrayLiteral:
+		return marshalArray(e)
+	}
+	kind, ok := literalKindOf(expr)
+	if !ok {
+		return "", nil, errors.Errorf("%s is not a literal", expr.SQL())
+	}
+	value, err := marshalScalar(expr)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(kind.typeName), value, nil
+}
+
+func marshalArray(lit *ast.ArrayLiteral) (string, json.RawMessage, error) {
+	name := ""
+	if lit.Type != nil {
+		var err error
+		name, err = jsonTypeName(lit.Type)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	values := make([]json.RawMessage, 0, len(lit.Values))
+	for _, v := range lit.Values {
+		if _, ok := v.(*ast.ArrayLiteral); ok {
+			return "", nil, errors.New("ARRAY can't contain ARRAY")
+		}
+		elemName, value, err := MarshalLiteral(v)
+		if err != nil {
+			return "", nil, err
+		}
+		if elemName != "" {
+			if name != "" && name != elemName {
+				return "", nil, errors.Errorf("ARRAY<%s> can't contain %s", name, elemName)
+			}
+			name = elemName
+		}
+		values = append(values, value)
+	}
+	value, err := json.Marshal(values)
+	if err != nil {
+		return "", nil, err
+	}
+	if name == "" {
+		return "", value, nil
+	}
+	return "ARRAY<" + name + ">", value, nil
+}
+
+func marshalScalar(expr ast.Expr) (json.RawMessage, error) {
+	var v interface{}
+	switch e := expr.(type) {
+	case *ast.NullLiteral:
+		v = nil
+	case *ast.BoolLiteral:
+		v = e.Value
+	case *ast.StringLiteral:
+		v = e.Value
+	case *ast.BytesLiteral:
+		v = e.Value
+	case *ast.IntLiteral:
+		i, err := int64Kind.decode(e)
+		if err != nil {
+			return nil, err
+		}
+		v = strconv.FormatInt(i.(int64), 10)
+	case *ast.FloatLiteral:
+		f, err := float64Kind.decode(e)
+		if err != nil {
+			return nil, err
+		}
+		switch x := f.(float64); {
+		case math.IsNaN(x):
+			v = "NaN"
+		case math.IsInf(x, 1):
+			v = "Infinity"
+		case math.IsInf(x, -1):
+			v = "-Infinity"
+		default:
+			v = x
+		}
+	case *ast.TimestampLiteral:
+		v = e.Value.Value
+	case *ast.DateLiteral:
+		v = e.Value.Value
+	case *ast.NumericLiteral:
+		v = e.Value.Value
+	default:
+		return nil, errors.Errorf("%s is not a scalar literal", expr.SQL())
+	}
+	return json.Marshal(v)
+}
+
+// jsonTypeName returns the name of typ used in JSON representation, like `INT64` or `ARRAY<STRING>`.
+func jsonTypeName(typ ast.Type) (string, error) {
+	switch t := typ.(type) {
+	case *ast.SimpleType:
+		return string(t.Name), nil
+	case *ast.ArrayType:
+		name, err := jsonTypeName(t.Item)
+		if err != nil {
+			return "", err
+		}
+		return "ARRAY<" + name + ">", nil
+	default:
+		return "", errors.Errorf("type %s can't be represented in JSON", typ.SQL())
+	}
+}
+
+// UnmarshalLiteral converts a pair of a type name and JSON value into a literal expression.
+// It is the inverse of MarshalLiteral. In addition, if the type name is empty,
+// the type is inferred from the JSON value (numbers are INT64 if they are integers, and FLOAT64 otherwise).
+func UnmarshalLiteral(name string, value json.RawMessage) (ast.Expr, error) {
+	if name == "" {
+		return inferLiteral(value)
+	}
+	if strings.HasPrefix(name, "ARRAY<") && strings.HasSuffix(name, ">") {
+		elemName := strings.TrimSuffix(strings.TrimPrefix(name, "ARRAY<"), ">")
+		elemType, ok := scalarType(elemName)
+		if !ok {
+			return nil, errors.Errorf("unknown type %s", name)
+		}
+		if isJSONNull(value) {
+			return TypedNullLit(&ast.ArrayType{Item: elemType}), nil
+		}
+		var values []json.RawMessage
+		if err := json.Unmarshal(value, &values); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		exprs := make([]ast.Expr, 0, len(values))
+		for _, v := range values {
+			expr, err := unmarshalScalar(elemName, v)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, UntypeExpr(expr))
+		}
+		lit := ArrayLit(exprs)
+		lit.Type = elemType
+		return lit, nil
+	}
+	typ, ok := scalarType(name)
+	if !ok {
+		return nil, errors.Errorf("unknown type %s", name)
+	}
+	if isJSONNull(value) {
+		return TypedNullLit(typ), nil
+	}
+	return unmarshalScalar(name, value)
+}
+
+func scalarType(name string) (*ast.SimpleType, bool) {
+	switch n := ast.ScalarTypeName(name); n {
+	case ast.BoolTypeName, Float32TypeName:
+		return simpleType(n), true
+	default:
+		if _, ok := literalKindsByType[n]; !ok {
+			return nil, false
+		}
+		return simpleType(n), true
+	}
+}
+
+func isJSONNull(value json.RawMessage) bool {
+	return string(bytes.TrimSpace(value)) == "null"
+}
+
+func unmarshalScalar(name string, value json.RawMessage) (ast.Expr, error) {
+	if isJSONNull(value) {
+		return TypedNullLit(simpleType(ast.ScalarTypeName(name))), nil
+	}
+	switch ast.ScalarTypeName(name) {
+	case ast.BoolTypeName:
+		var v bool
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		return BoolLit(v), nil
+	case ast.StringTypeName:
+		var v string
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		return StringLit(v), nil
+	case ast.BytesTypeName:
+		var v []byte
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		return BytesLit(v), nil
+	case ast.Int64TypeName:
+		s, err := unmarshalNumberString(name, value)
+		if err != nil {
+			return nil, err
+		}
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		return IntLit(v), nil
+	case ast.Float64TypeName, Float32TypeName:
+		s, err := unmarshalNumberString(name, value)
+		if err != nil {
+			return nil, err
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		if name == string(Float32TypeName) {
+			return Float32Lit(float32(v)), nil
+		}
+		return FloatLit(v), nil
+	case ast.TimestampTypeName:
+		var v time.Time
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		return TimeLit(v), nil
+	case ast.DateTypeName:
+		var v civil.Date
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s value", name)
+		}
+		return DateLit(v), nil
+	case ast.NumericTypeName:
+		s, err := unmarshalNumberString(name, value)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return nil, errors.Errorf("invalid %s value %s", name, s)
+		}
+		return NumericLit(v)
+	default:
+		return nil, errors.Errorf("unknown type %s", name)
+	}
+}
+
+// unmarshalNumberString accepts both JSON numbers and strings that contain numbers.
+func unmarshalNumberString(name string, value json.RawMessage) (string, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(value))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return "", errors.Wrapf(err, "invalid %s value", name)
+	}
+	switch n := v.(type) {
+	case json.Number:
+		return n.String(), nil
+	case string:
+		return n, nil
+	default:
+		return "", errors.Errorf("invalid %s value %s", name, string(value))
+	}
+}
+
+func inferLiteral(value json.RawMessage) (ast.Expr, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(value))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, errors.Wrap(err, "invalid value")
+	}
+	return inferredLiteral(v)
+}
+
+func inferredLiteral(v interface{}) (ast.Expr, error) {
+	switch x := v.(type) {
+	case nil:
+		return NullLit(), nil
+	case bool:
+		return BoolLit(x), nil
+	case string:
+		return StringLit(x), nil
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return IntLit(i), nil
+		}
+		f, err := x.Float64()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid number %s", x.String())
+		}
+		return FloatLit(f), nil
+	case []interface{}:
+		exprs := make([]ast.Expr, 0, len(x))
+		for _, elem := range x {
+			if _, ok := elem.([]interface{}); ok {
+				return nil, errors.New("ARRAY can't contain ARRAY")
+			}
+			expr, err := inferredLiteral(elem)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+		return ArrayLit(exprs), nil
+	default:
+		return nil, errors.Errorf("can't convert %s into a literal", string(mustMarshal(v)))
+	}
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -0,0 +1,67 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+func TestMarshalLiteral(t *testing.T) {
+	testCases := []struct {
+		expr          ast.Expr
+		expectedType  string
+		expectedValue string
+	}{
+		{internal.NullLit(), ``, `null`},
+		{internal.StringLit("a"), `STRING`, `"a"`},
+		{internal.IntLit(-1), `INT64`, `"-1"`},
+		{&ast.IntLiteral{Base: 16, Value: "ff"}, `INT64`, `"255"`},
+		{internal.ArrayLit([]ast.Expr{internal.NullLit()}), ``, `[null]`},
+		{internal.ArrayLit([]ast.Expr{internal.NullLit(), internal.IntLit(1)}), `ARRAY<INT64>`, `[null,"1"]`},
+	}
+	for _, tc := range testCases {
+		typ, value, err := internal.MarshalLiteral(tc.expr)
+		assert.Nil(t, err, tc.expr.SQL())
+		assert.Equal(t, tc.expectedType, typ, tc.expr.SQL())
+		assert.Equal(t, tc.expectedValue, string(value), tc.expr.SQL())
+	}
+}
+
+func TestMarshalLiteralWithNonLiterals(t *testing.T) {
+	for _, expr := range []ast.Expr{
+		&ast.Ident{Name: "a"},
+		internal.ArrayLit([]ast.Expr{internal.IntLit(1), internal.StringLit("a")}),
+		internal.ArrayLit([]ast.Expr{internal.ArrayLit(nil)}),
+		&ast.StructLiteral{Values: []ast.Expr{internal.IntLit(1)}},
+	} {
+		_, _, err := internal.MarshalLiteral(expr)
+		assert.Error(t, err, expr.SQL())
+	}
+}
+
+func TestUnmarshalLiteral(t *testing.T) {
+	testCases := []struct {
+		typ      string
+		value    string
+		expected string
+	}{
+		{``, `1`, `1`},
+		{``, `1e3`, `1e+03`},
+		{``, `99999999999999999999`, `1e+20`},
+		{`INT64`, `1`, `1`},
+		{`INT64`, `"1"`, `1`},
+		{`FLOAT64`, `"-Infinity"`, `-Inf`},
+		{`NUMERIC`, `1.25`, `NUMERIC "1.25"`},
+		{`BOOL`, `null`, `CAST(NULL AS BOOL)`},
+		{`ARRAY<DATE>`, `["2021-01-02", null]`, `ARRAY<DATE>[DATE "2021-01-02", NULL]`},
+	}
+	for _, tc := range testCases {
+		expr, err := internal.UnmarshalLiteral(tc.typ, json.RawMessage(tc.value))
+		assert.Nil(t, err, tc.value)
+		assert.Equal(t, tc.expected, expr.SQL(), tc.value)
+	}
+}
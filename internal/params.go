@@ -0,0 +1,273 @@
+package internal
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/pkg/errors"
+)
+
+// Parameterize replaces literal values in node with query parameters named @p1, @p2, ...
+// and returns a map from parameter names to their values.
+// Identical literals share the same parameter, and names that are already used in node are skipped.
+//
+// NULLs and BOOL literals are kept as is since they neither leak data nor bloat query text.
+func Parameterize(node ast.Node) (map[string]interface{}, error) {
+	used := ParamNames(node)
+	params := map[string]interface{}{}
+	names := map[string]string{}
+	n := 0
+	err := Rewrite(node, func(expr ast.Expr) (ast.Expr, error) {
+		val, ok, err := LiteralValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return expr, nil
+		}
+		key := expr.SQL()
+		name, ok := names[key]
+		if !ok {
+			for {
+				n++
+				name = "p" + strconv.Itoa(n)
+				if !used[name] {
+					break
+				}
+			}
+			names[key] = name
+			params[name] = val
+		}
+		return &ast.Param{Name: name}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// ParamNames returns a set of the names of query parameters used in node.
+func ParamNames(node ast.Node) map[string]bool {
+	names := map[string]bool{}
+	// The function never fails.
+	_ = Rewrite(node, func(expr ast.Expr) (ast.Expr, error) {
+		if p, ok := expr.(*ast.Param); ok {
+			names[p.Name] = true
+		}
+		return expr, nil
+	})
+	return names
+}
+
+// LiteralValue returns a Go value that is represented by the given literal expression.
+// It returns false if expr is not a literal that can be used as a query parameter.
+func LiteralValue(expr ast.Expr) (interface{}, bool, error) {
+	if lit, ok := expr.(*ast.ArrayLiteral); ok {
+		return arrayLiteralValue(lit)
+	}
+	kind, ok := literalKindOf(expr)
+	if !ok {
+		return nil, false, nil
+	}
+	val, err := kind.decode(expr)
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+func arrayLiteralValue(lit *ast.ArrayLiteral) (interface{}, bool, error) {
+	var kind *literalKind
+	if typ, ok := lit.Type.(*ast.SimpleType); ok {
+		kind, ok = literalKindsByType[typ.Name]
+		if !ok {
+			return nil, false, nil
+		}
+	}
+	hasNull := false
+	for _, v := range lit.Values {
+		if _, ok := v.(*ast.NullLiteral); ok {
+			hasNull = true
+			continue
+		}
+		k, ok := literalKindOf(v)
+		if !ok || (kind != nil && kind != k) {
+			return nil, false, nil
+		}
+		kind = k
+	}
+	if kind == nil {
+		// The type of the array can't be determined.
+		return nil, false, nil
+	}
+	elemType := kind.typ
+	if hasNull {
+		elemType = kind.nullType
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(lit.Values))
+	for _, v := range lit.Values {
+		if _, ok := v.(*ast.NullLiteral); ok {
+			slice = reflect.Append(slice, reflect.Zero(elemType))
+			continue
+		}
+		val, err := kind.decode(v)
+		if err != nil {
+			return nil, false, err
+		}
+		if hasNull {
+			val = kind.toNull(val)
+		}
+		slice = reflect.Append(slice, reflect.ValueOf(val))
+	}
+	return slice.Interface(), true, nil
+}
+
+// literalKind describes how to convert literals of a specific type into Go values.
+type literalKind struct {
+	typeName ast.ScalarTypeName
+	typ      reflect.Type
+	nullType reflect.Type
+	decode   func(ast.Expr) (interface{}, error)
+	toNull   func(interface{}) interface{}
+}
+
+var (
+	stringKind = &literalKind{
+		typeName: ast.StringTypeName,
+		typ:      reflect.TypeOf(""),
+		nullType: nullStringType,
+		decode: func(e ast.Expr) (interface{}, error) {
+			return e.(*ast.StringLiteral).Value, nil
+		},
+		toNull: func(v interface{}) interface{} {
+			return spanner.NullString{StringVal: v.(string), Valid: true}
+		},
+	}
+	bytesKind = &literalKind{
+		typeName: ast.BytesTypeName,
+		typ:      reflect.TypeOf([]byte(nil)),
+		nullType: reflect.TypeOf([]byte(nil)),
+		decode: func(e ast.Expr) (interface{}, error) {
+			return e.(*ast.BytesLiteral).Value, nil
+		},
+		toNull: func(v interface{}) interface{} {
+			return v
+		},
+	}
+	int64Kind = &literalKind{
+		typeName: ast.Int64TypeName,
+		typ:      reflect.TypeOf(int64(0)),
+		nullType: nullInt64Type,
+		decode: func(e ast.Expr) (interface{}, error) {
+			lit := e.(*ast.IntLiteral)
+			v, err := strconv.ParseInt(lit.Value, lit.Base, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid INT64 literal %s", lit.Value)
+			}
+			return v, nil
+		},
+		toNull: func(v interface{}) interface{} {
+			return spanner.NullInt64{Int64: v.(int64), Valid: true}
+		},
+	}
+	float64Kind = &literalKind{
+		typeName: ast.Float64TypeName,
+		typ:      reflect.TypeOf(float64(0)),
+		nullType: nullFloat64Type,
+		decode: func(e ast.Expr) (interface{}, error) {
+			lit := e.(*ast.FloatLiteral)
+			v, err := strconv.ParseFloat(lit.Value, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid FLOAT64 literal %s", lit.Value)
+			}
+			return v, nil
+		},
+		toNull: func(v interface{}) interface{} {
+			return spanner.NullFloat64{Float64: v.(float64), Valid: true}
+		},
+	}
+	timestampKind = &literalKind{
+		typeName: ast.TimestampTypeName,
+		typ:      timeType,
+		nullType: nullTimeType,
+		decode: func(e ast.Expr) (interface{}, error) {
+			lit := e.(*ast.TimestampLiteral)
+			v, err := time.Parse(time.RFC3339Nano, lit.Value.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid TIMESTAMP literal %s", lit.Value.Value)
+			}
+			return v, nil
+		},
+		toNull: func(v interface{}) interface{} {
+			return spanner.NullTime{Time: v.(time.Time), Valid: true}
+		},
+	}
+	dateKind = &literalKind{
+		typeName: ast.DateTypeName,
+		typ:      dateType,
+		nullType: nullDateType,
+		decode: func(e ast.Expr) (interface{}, error) {
+			lit := e.(*ast.DateLiteral)
+			v, err := civil.ParseDate(lit.Value.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid DATE literal %s", lit.Value.Value)
+			}
+			return v, nil
+		},
+		toNull: func(v interface{}) interface{} {
+			return spanner.NullDate{Date: v.(civil.Date), Valid: true}
+		},
+	}
+	numericKind = &literalKind{
+		typeName: ast.NumericTypeName,
+		typ:      ratType,
+		nullType: nullNumericType,
+		decode: func(e ast.Expr) (interface{}, error) {
+			lit := e.(*ast.NumericLiteral)
+			v, ok := new(big.Rat).SetString(lit.Value.Value)
+			if !ok {
+				return nil, errors.Errorf("invalid NUMERIC literal %s", lit.Value.Value)
+			}
+			return *v, nil
+		},
+		toNull: func(v interface{}) interface{} {
+			return spanner.NullNumeric{Numeric: v.(big.Rat), Valid: true}
+		},
+	}
+)
+
+var literalKindsByType = map[ast.ScalarTypeName]*literalKind{
+	ast.StringTypeName:    stringKind,
+	ast.BytesTypeName:     bytesKind,
+	ast.Int64TypeName:     int64Kind,
+	ast.Float64TypeName:   float64Kind,
+	ast.TimestampTypeName: timestampKind,
+	ast.DateTypeName:      dateKind,
+	ast.NumericTypeName:   numericKind,
+}
+
+func literalKindOf(expr ast.Expr) (*literalKind, bool) {
+	switch expr.(type) {
+	case *ast.StringLiteral:
+		return stringKind, true
+	case *ast.BytesLiteral:
+		return bytesKind, true
+	case *ast.IntLiteral:
+		return int64Kind, true
+	case *ast.FloatLiteral:
+		return float64Kind, true
+	case *ast.TimestampLiteral:
+		return timestampKind, true
+	case *ast.DateLiteral:
+		return dateKind, true
+	case *ast.NumericLiteral:
+		return numericKind, true
+	default:
+		return nil, false
+	}
+}
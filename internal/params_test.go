@@ -0,0 +1,68 @@
+package internal_test
+
+import (
+	"math"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+func testLiteralValue(t *testing.T, val interface{}, expected interface{}) {
+	expr, err := internal.ToExpr(val)
+	assert.Nil(t, err)
+	actual, ok, err := internal.LiteralValue(expr)
+	assert.Nil(t, err)
+	assert.True(t, ok, "%s is not a literal", expr.SQL())
+	assert.Equal(t, expected, actual)
+}
+
+func TestLiteralValue(t *testing.T) {
+	testLiteralValue(t, "foo", "foo")
+	testLiteralValue(t, 123, int64(123))
+	testLiteralValue(t, uint8(12), int64(12))
+	testLiteralValue(t, 1.5, 1.5)
+	testLiteralValue(t, math.Inf(-1), math.Inf(-1))
+	testLiteralValue(t, []byte{1, 2}, []byte{1, 2})
+	testLiteralValue(t, []string{"a"}, []string{"a"})
+	testLiteralValue(t, []spanner.NullInt64{{Int64: 1, Valid: true}, {}}, []spanner.NullInt64{{Int64: 1, Valid: true}, {}})
+	testLiteralValue(t, []*string{nil}, []spanner.NullString{{}})
+	testLiteralValue(t, [][]byte{{1}, nil}, [][]byte{{1}, nil})
+}
+
+func TestLiteralValueWithNonLiterals(t *testing.T) {
+	for _, expr := range []ast.Expr{
+		internal.NullLit(),
+		internal.BoolLit(true),
+		internal.TypedNullLit(&ast.SimpleType{Name: ast.StringTypeName}),
+		internal.ArrayLit([]ast.Expr{}),
+		internal.ArrayLit([]ast.Expr{internal.NullLit()}),
+		internal.ArrayLit([]ast.Expr{internal.IntLit(1), internal.StringLit("a")}),
+		internal.ArrayLit([]ast.Expr{&ast.Ident{Name: "a"}}),
+		&ast.Ident{Name: "a"},
+	} {
+		_, ok, err := internal.LiteralValue(expr)
+		assert.Nil(t, err)
+		assert.False(t, ok, expr.SQL())
+	}
+}
+
+func TestParameterize(t *testing.T) {
+	stmt := &ast.Delete{
+		TableName: &ast.Ident{Name: "hoge"},
+		Where: &ast.Where{
+			Expr: &ast.BinaryExpr{
+				Op:    ast.OpAnd,
+				Left:  &ast.BinaryExpr{Op: ast.OpEqual, Left: &ast.Param{Name: "p1"}, Right: internal.IntLit(1)},
+				Right: &ast.BinaryExpr{Op: ast.OpEqual, Left: &ast.Param{Name: "p3"}, Right: internal.StringLit("1")},
+			},
+		},
+	}
+	params, err := internal.Parameterize(stmt)
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM hoge WHERE @p1 = @p2 AND @p3 = @p4`, stmt.SQL())
+	assert.Equal(t, map[string]interface{}{"p2": int64(1), "p4": "1"}, params)
+}
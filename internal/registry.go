@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ValueConverter converts a Go value into another Go value that can be converted into SQL expression.
+type ValueConverter func(v interface{}) (interface{}, error)
+
+type registry struct {
+	mu         sync.RWMutex
+	types      map[reflect.Type]ValueConverter
+	interfaces []registeredInterface
+}
+
+type registeredInterface struct {
+	typ  reflect.Type
+	conv ValueConverter
+}
+
+var converters = &registry{
+	types: map[reflect.Type]ValueConverter{},
+}
+
+// RegisterConverter registers conv as a converter for values of type typ.
+// If typ is an interface type, conv is used for all values that implement typ.
+// Registering a converter for the same type again replaces the existing one.
+func RegisterConverter(typ reflect.Type, conv ValueConverter) {
+	converters.mu.Lock()
+	defer converters.mu.Unlock()
+	if typ.Kind() != reflect.Interface {
+		converters.types[typ] = conv
+		return
+	}
+	for i, iface := range converters.interfaces {
+		if iface.typ == typ {
+			converters.interfaces[i].conv = conv
+			return
+		}
+	}
+	converters.interfaces = append(converters.interfaces, registeredInterface{typ: typ, conv: conv})
+}
+
+// lookupConverter returns a converter registered for typ.
+// Converters registered for concrete types take precedence over ones for interface types,
+// and interface types are checked in the order they are registered.
+func lookupConverter(typ reflect.Type) (ValueConverter, bool) {
+	converters.mu.RLock()
+	defer converters.mu.RUnlock()
+	if conv, ok := converters.types[typ]; ok {
+		return conv, true
+	}
+	for _, iface := range converters.interfaces {
+		if typ.Implements(iface.typ) {
+			return iface.conv, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,62 @@
+package internal_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+type testRegisteredID [2]byte
+
+type testRegisteredNamer interface {
+	TestName() string
+}
+
+type testRegisteredStringer struct {
+	name string
+}
+
+func (s testRegisteredStringer) TestName() string {
+	return "stringer " + s.name
+}
+
+type testRegisteredFailure struct{}
+
+func init() {
+	internal.RegisterConverter(reflect.TypeOf(testRegisteredID{}), func(v interface{}) (interface{}, error) {
+		id := v.(testRegisteredID)
+		return fmt.Sprintf("%02x%02x", id[0], id[1]), nil
+	})
+	internal.RegisterConverter(reflect.TypeOf((*testRegisteredNamer)(nil)).Elem(), func(v interface{}) (interface{}, error) {
+		return v.(testRegisteredNamer).TestName(), nil
+	})
+	internal.RegisterConverter(reflect.TypeOf(testRegisteredFailure{}), func(v interface{}) (interface{}, error) {
+		return nil, errToASTExprFailed
+	})
+}
+
+func TestASTWithRegisteredConverter(t *testing.T) {
+	testAST(t, testRegisteredID{0x12, 0xab}, internal.StringLit("12ab"))
+	var id = testRegisteredID{0, 1}
+	testAST(t, &id, internal.StringLit("0001"))
+	testAST(t, (*testRegisteredID)(nil), internal.NullLit())
+	testAST(t,
+		[]testRegisteredID{{0, 1}},
+		internal.ArrayLit([]ast.Expr{internal.StringLit("0001")}),
+	)
+	testAST(t, []testRegisteredID{}, internal.ArrayLit([]ast.Expr{}))
+}
+
+func TestASTWithConverterRegisteredForInterface(t *testing.T) {
+	testAST(t, testRegisteredStringer{name: "hoge"}, internal.StringLit("stringer hoge"))
+}
+
+func TestASTWhenRegisteredConverterFails(t *testing.T) {
+	_, err := internal.ToExpr(testRegisteredFailure{})
+	assert.ErrorIs(t, err, errToASTExprFailed)
+}
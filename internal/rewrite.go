@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"github.com/MakeNowJust/memefish/pkg/ast"
+)
+
+// RewriteFunc is a function that is called by Rewrite on each expression.
+// If it returns the given expression itself, Rewrite continues to walk through its children.
+// Otherwise the expression is replaced with the returned one and its children are skipped.
+type RewriteFunc func(ast.Expr) (ast.Expr, error)
+
+// Rewrite walks through all expressions in node and replaces them with the result of f.
+// Note that node is modified in place, and node itself is never replaced even if it is an expression.
+func Rewrite(node ast.Node, f RewriteFunc) error {
+	w := &rewriter{f: f}
+	w.node(node)
+	return w.err
+}
+
+type rewriter struct {
+	f   RewriteFunc
+	err error
+}
+
+func (w *rewriter) node(node ast.Node) {
+	if w.err != nil || node == nil {
+		return
+	}
+	switch n := node.(type) {
+	case *ast.QueryStatement:
+		w.node(n.Query)
+	case *ast.Select:
+		for _, item := range n.Results {
+			w.node(item)
+		}
+		if n.From != nil {
+			w.node(n.From.Source)
+		}
+		if n.Where != nil {
+			w.expr(&n.Where.Expr)
+		}
+		if n.GroupBy != nil {
+			w.exprs(n.GroupBy.Exprs)
+		}
+		if n.Having != nil {
+			w.expr(&n.Having.Expr)
+		}
+		if n.OrderBy != nil {
+			for _, item := range n.OrderBy.Items {
+				w.expr(&item.Expr)
+			}
+		}
+	case *ast.SubQuery:
+		w.node(n.Query)
+	case *ast.CompoundQuery:
+		for _, q := range n.Queries {
+			w.node(q)
+		}
+	case *ast.ExprSelectItem:
+		w.expr(&n.Expr)
+	case *ast.Alias:
+		w.expr(&n.Expr)
+	case *ast.DotStar:
+		w.expr(&n.Expr)
+	case *ast.SubQueryTableExpr:
+		w.node(n.Query)
+	case *ast.ParenTableExpr:
+		w.node(n.Source)
+	case *ast.Join:
+		w.node(n.Left)
+		w.node(n.Right)
+		if on, ok := n.Cond.(*ast.On); ok {
+			w.expr(&on.Expr)
+		}
+	case *ast.Unnest:
+		w.expr(&n.Expr)
+	case *ast.Insert:
+		w.node(n.Input)
+	case *ast.ValuesInput:
+		for _, row := range n.Rows {
+			for _, e := range row.Exprs {
+				if !e.Default {
+					w.expr(&e.Expr)
+				}
+			}
+		}
+	case *ast.SubQueryInput:
+		w.node(n.Query)
+	case *ast.Update:
+		for _, item := range n.Updates {
+			w.expr(&item.Expr)
+		}
+		if n.Where != nil {
+			w.expr(&n.Where.Expr)
+		}
+	case *ast.Delete:
+		if n.Where != nil {
+			w.expr(&n.Where.Expr)
+		}
+	case *ast.Where:
+		w.expr(&n.Expr)
+	case *DML:
+		w.node(n.Stmt)
+		if n.ThenReturn != nil {
+			for _, item := range n.ThenReturn.Items {
+				w.node(item)
+			}
+		}
+	case ast.Expr:
+		w.children(n)
+	}
+}
+
+func (w *rewriter) exprs(exprs []ast.Expr) {
+	for i := range exprs {
+		w.expr(&exprs[i])
+	}
+}
+
+func (w *rewriter) expr(p *ast.Expr) {
+	if w.err != nil || *p == nil {
+		return
+	}
+	e, err := w.f(*p)
+	if err != nil {
+		w.err = err
+		return
+	}
+	if e != *p {
+		*p = e
+		return
+	}
+	w.children(e)
+}
+
+func (w *rewriter) children(expr ast.Expr) {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		w.expr(&e.Left)
+		w.expr(&e.Right)
+	case *ast.UnaryExpr:
+		w.expr(&e.Expr)
+	case *ast.InExpr:
+		w.expr(&e.Left)
+		switch r := e.Right.(type) {
+		case *ast.UnnestInCondition:
+			w.expr(&r.Expr)
+		case *ast.ValuesInCondition:
+			w.exprs(r.Exprs)
+		case *ast.SubQueryInCondition:
+			w.node(r.Query)
+		}
+	case *ast.IsNullExpr:
+		w.expr(&e.Left)
+	case *ast.IsBoolExpr:
+		w.expr(&e.Left)
+	case *ast.BetweenExpr:
+		w.expr(&e.Left)
+		w.expr(&e.RightStart)
+		w.expr(&e.RightEnd)
+	case *ast.SelectorExpr:
+		w.expr(&e.Expr)
+	case *ast.IndexExpr:
+		w.expr(&e.Expr)
+		w.expr(&e.Index)
+	case *ast.CallExpr:
+		for _, arg := range e.Args {
+			w.expr(&arg.Expr)
+		}
+	case *ast.CastExpr:
+		w.expr(&e.Expr)
+	case *ast.ExtractExpr:
+		w.expr(&e.Expr)
+	case *ast.CaseExpr:
+		w.expr(&e.Expr)
+		for _, when := range e.Whens {
+			w.expr(&when.Cond)
+			w.expr(&when.Then)
+		}
+		if e.Else != nil {
+			w.expr(&e.Else.Expr)
+		}
+	case *ast.ParenExpr:
+		w.expr(&e.Expr)
+	case *ast.ScalarSubQuery:
+		w.node(e.Query)
+	case *ast.ArraySubQuery:
+		w.node(e.Query)
+	case *ast.ExistsSubQuery:
+		w.node(e.Query)
+	case *ast.ArrayLiteral:
+		w.exprs(e.Values)
+	case *ast.StructLiteral:
+		w.exprs(e.Values)
+	}
+}
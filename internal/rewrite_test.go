@@ -0,0 +1,94 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+func TestRewrite(t *testing.T) {
+	stmt := &ast.Select{
+		Results: []ast.SelectItem{
+			&ast.ExprSelectItem{Expr: &ast.Ident{Name: "a"}},
+			&ast.ExprSelectItem{Expr: &ast.ScalarSubQuery{
+				Query: &ast.Select{
+					Results: []ast.SelectItem{
+						&ast.ExprSelectItem{Expr: internal.IntLit(1)},
+					},
+				},
+			}},
+		},
+		From: &ast.From{
+			Source: &ast.TableName{Table: &ast.Ident{Name: "hoge"}},
+		},
+		Where: &ast.Where{
+			Expr: &ast.BinaryExpr{
+				Op:    ast.OpAnd,
+				Left:  &ast.BinaryExpr{Op: ast.OpEqual, Left: &ast.Ident{Name: "a"}, Right: internal.IntLit(2)},
+				Right: &ast.InExpr{Left: &ast.Ident{Name: "b"}, Right: &ast.UnnestInCondition{Expr: internal.ArrayLit([]ast.Expr{internal.IntLit(3)})}},
+			},
+		},
+	}
+	err := internal.Rewrite(stmt, func(expr ast.Expr) (ast.Expr, error) {
+		if _, ok := expr.(*ast.IntLiteral); ok {
+			return &ast.Param{Name: "n"}, nil
+		}
+		return expr, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT a, (SELECT @n) FROM hoge WHERE a = @n AND b IN UNNEST(ARRAY[@n])`, stmt.SQL())
+}
+
+func TestRewriteSkipsChildrenOfReplacedExpr(t *testing.T) {
+	stmt := &ast.Delete{
+		TableName: &ast.Ident{Name: "hoge"},
+		Where: &ast.Where{
+			Expr: &ast.InExpr{Left: &ast.Ident{Name: "a"}, Right: &ast.UnnestInCondition{Expr: internal.ArrayLit([]ast.Expr{internal.IntLit(1)})}},
+		},
+	}
+	var visited []string
+	err := internal.Rewrite(stmt, func(expr ast.Expr) (ast.Expr, error) {
+		visited = append(visited, expr.SQL())
+		if _, ok := expr.(*ast.ArrayLiteral); ok {
+			return &ast.Param{Name: "arr"}, nil
+		}
+		return expr, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`a IN UNNEST(ARRAY[1])`, `a`, `ARRAY[1]`}, visited)
+	assert.Equal(t, `DELETE FROM hoge WHERE a IN UNNEST(@arr)`, stmt.SQL())
+}
+
+func TestRewriteWhenFuncFails(t *testing.T) {
+	stmt := &ast.Update{
+		TableName: &ast.Ident{Name: "hoge"},
+		Updates: []*ast.UpdateItem{
+			{Path: []*ast.Ident{{Name: "a"}}, Expr: internal.IntLit(1)},
+		},
+		Where: &ast.Where{Expr: internal.BoolLit(true)},
+	}
+	err := internal.Rewrite(stmt, func(expr ast.Expr) (ast.Expr, error) {
+		return nil, errToASTExprFailed
+	})
+	assert.ErrorIs(t, err, errToASTExprFailed)
+}
+
+func TestRewriteWithDML(t *testing.T) {
+	stmt := &internal.DML{
+		Stmt: &ast.Delete{
+			TableName: &ast.Ident{Name: "hoge"},
+			Where:     &ast.Where{Expr: &ast.BinaryExpr{Op: ast.OpEqual, Left: &ast.Ident{Name: "a"}, Right: internal.IntLit(1)}},
+		},
+	}
+	err := internal.Rewrite(stmt, func(expr ast.Expr) (ast.Expr, error) {
+		if _, ok := expr.(*ast.IntLiteral); ok {
+			return &ast.Param{Name: "n"}, nil
+		}
+		return expr, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM hoge WHERE a = @n`, stmt.SQL())
+}
@@ -0,0 +1,236 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/pkg/errors"
+)
+
+// ColumnName returns the column name that corresponds to the given struct field.
+// It returns false if the field should be ignored.
+//
+// If the field has `spanner:"Name"` tag, its value is used as column name, otherwise the field name is used.
+// Fields with `spanner:"-"` tag and unexported fields are ignored.
+// Options that follow the name in the tag (e.g. `spanner:"Name,readonly"`) are not part of the column name.
+func ColumnName(field *reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("spanner")
+	if tag == "-" {
+		return "", false
+	}
+	name, _ := parseTag(tag)
+	if name == "" {
+		return field.Name, true
+	}
+	return name, true
+}
+
+// parseTag splits `spanner` tag into a column name and options.
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// StructField is a field of a struct type that is mapped to a column.
+type StructField struct {
+	// Name is the column name.
+	Name string
+	// Index is the index sequence for reflect.Value.FieldByIndex.
+	Index []int
+	// Type is the type of the field.
+	Type reflect.Type
+	// Tagged reports whether Name is given by `spanner` tag.
+	Tagged bool
+	// CommitTimestamp reports whether the field has `commit_ts` option.
+	// PENDING_COMMIT_TIMESTAMP() is written to the column instead of the value of the field.
+	CommitTimestamp bool
+	// ReadOnly reports whether the field has `readonly` or `generated` option.
+	// The column is never written.
+	ReadOnly bool
+	// Default reports whether the field has `default` option.
+	// DEFAULT is written to the column if the field has zero value.
+	Default bool
+}
+
+// Matches reports whether the field corresponds to the column colName.
+// Column names derived from field names are compared case-insensitively.
+func (f *StructField) Matches(colName string) bool {
+	if f.Tagged {
+		return f.Name == colName
+	}
+	return strings.EqualFold(f.Name, colName)
+}
+
+// Value returns the value of the field in v, whose type is the struct type given to StructFields.
+// If the field belongs to an embedded struct that is a nil pointer, it returns a nil pointer to the type of the field.
+func (f *StructField) Value(v reflect.Value) reflect.Value {
+	for _, i := range f.Index[:len(f.Index)-1] {
+		v = v.Field(i)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Zero(reflect.PtrTo(f.Type))
+			}
+			v = v.Elem()
+		}
+	}
+	return v.Field(f.Index[len(f.Index)-1])
+}
+
+// StructFields returns the fields of the struct type t that are mapped to columns in declaration order.
+//
+// Fields of anonymous embedded structs (and pointers to them) that don't have `spanner` tags are flattened into columns.
+// If more than one field has the same column name, the shallowest one is used as Go does for promoted fields.
+// It returns an error if the column name is still ambiguous.
+func StructFields(t reflect.Type) ([]StructField, error) {
+	var fields []StructField
+	if err := collectStructFields(t, nil, map[reflect.Type]bool{}, &fields); err != nil {
+		return nil, err
+	}
+
+	// Column names are case-insensitive in Spanner.
+	shallowest := map[string]int{}
+	for _, f := range fields {
+		key := strings.ToLower(f.Name)
+		if d, ok := shallowest[key]; !ok || len(f.Index) < d {
+			shallowest[key] = len(f.Index)
+		}
+	}
+	result := make([]StructField, 0, len(fields))
+	found := map[string]bool{}
+	for _, f := range fields {
+		key := strings.ToLower(f.Name)
+		if len(f.Index) > shallowest[key] {
+			continue
+		}
+		if found[key] {
+			return nil, errors.Errorf("type %s has ambiguous column %s", t.String(), f.Name)
+		}
+		found[key] = true
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+// collectStructFields appends columns in t to fields, flattening embedded structs.
+// visiting holds struct types that are being visited to avoid infinite recursion on recursive types.
+func collectStructFields(t reflect.Type, index []int, visiting map[reflect.Type]bool, fields *[]StructField) error {
+	visiting[t] = true
+	defer delete(visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		if et, ok := embeddedStruct(&ft); ok {
+			if !visiting[et] {
+				if err := collectStructFields(et, fieldIndex, visiting, fields); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		name, ok := ColumnName(&ft)
+		if !ok {
+			continue
+		}
+		tagName, opts := parseTag(ft.Tag.Get("spanner"))
+		f := StructField{
+			Name:   name,
+			Index:  fieldIndex,
+			Type:   ft.Type,
+			Tagged: tagName != "",
+		}
+		for _, opt := range opts {
+			switch opt {
+			case "commit_ts":
+				f.CommitTimestamp = true
+			case "readonly", "generated":
+				f.ReadOnly = true
+			case "default":
+				f.Default = true
+			default:
+				return errors.Errorf("unknown option %s in tag of %s.%s", opt, t.String(), ft.Name)
+			}
+		}
+		*fields = append(*fields, f)
+	}
+	return nil
+}
+
+// embeddedStruct returns the struct type of field if the field should be flattened.
+func embeddedStruct(field *reflect.StructField) (reflect.Type, bool) {
+	if name, _ := parseTag(field.Tag.Get("spanner")); !field.Anonymous || name != "" {
+		return nil, false
+	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isScalarStruct(t) {
+		return nil, false
+	}
+	return t, true
+}
+
+// structType returns STRUCT<...> type that corresponds to t.
+// It returns false if t has no columns, the type of any field can't be determined, or t is being visited.
+// t is guaranteed to be a struct type here.
+func structType(t reflect.Type, visiting map[reflect.Type]bool) (*ast.StructType, bool) {
+	if visiting[t] {
+		return nil, false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	fields, err := StructFields(t)
+	if err != nil || len(fields) <= 0 {
+		return nil, false
+	}
+	astFields := make([]*ast.StructField, 0, len(fields))
+	for _, f := range fields {
+		typ, ok := typeOf(f.Type, visiting)
+		if !ok {
+			return nil, false
+		}
+		astFields = append(astFields, &ast.StructField{
+			Ident: &ast.Ident{Name: f.Name},
+			Type:  typ,
+		})
+	}
+	return &ast.StructType{Fields: astFields}, true
+}
+
+// structToExpr converts a Go struct into STRUCT literal.
+// It returns an error if the struct has no columns.
+// If types of all fields can be determined, it returns a typed STRUCT literal like `STRUCT<Name STRING>("foo")`.
+// Otherwise it returns an untyped one like `STRUCT("foo")`.
+// The type of valV is guaranteed to be struct here.
+func structToExpr(valV reflect.Value) (ast.Expr, error) {
+	t := valV.Type()
+	fields, err := StructFields(t)
+	if err != nil {
+		return nil, err
+	}
+	// Structs without columns are likely to be opaque values like decimals, which shouldn't be converted into empty STRUCTs.
+	if len(fields) <= 0 {
+		return nil, errors.Errorf("can't convert %s into SQL expr", t.String())
+	}
+	values := make([]ast.Expr, 0, len(fields))
+	for _, f := range fields {
+		expr, err := ToExpr(f.Value(valV).Interface())
+		if err != nil {
+			return nil, errors.WithMessagef(err, "at field %s", t.FieldByIndex(f.Index).Name)
+		}
+		values = append(values, expr)
+	}
+	lit := &ast.StructLiteral{Values: values}
+	if typ, ok := structType(t, map[reflect.Type]bool{}); ok {
+		lit.Fields = typ.Fields
+		// Fields don't need to be typed because the type of the STRUCT literal is explicitly given.
+		for i, v := range lit.Values {
+			lit.Values[i] = UntypeExpr(v)
+		}
+	}
+	return lit, nil
+}
@@ -0,0 +1,238 @@
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+type testStructPerson struct {
+	Name    string `spanner:"UserName"`
+	Age     int64
+	Tags    []string
+	Ignored string `spanner:"-"`
+	private string
+}
+
+type testStructWithInterface struct {
+	A interface{}
+	B string
+}
+
+type testStructNested struct {
+	ID     int64
+	Person testStructPerson
+}
+
+func testSQL(t *testing.T, val interface{}, expected string) {
+	expr, err := internal.ToExpr(val)
+	assert.Nil(t, err, expected)
+	assert.Equal(t, expected, expr.SQL())
+}
+
+func TestColumnName(t *testing.T) {
+	typ := reflect.TypeOf(testStructPerson{})
+	testCases := []struct {
+		field    string
+		expected string
+		ok       bool
+	}{
+		{"Name", "UserName", true},
+		{"Age", "Age", true},
+		{"Ignored", "", false},
+		{"private", "", false},
+	}
+	for _, tc := range testCases {
+		f, _ := typ.FieldByName(tc.field)
+		name, ok := internal.ColumnName(&f)
+		assert.Equal(t, tc.ok, ok, tc.field)
+		assert.Equal(t, tc.expected, name, tc.field)
+	}
+}
+
+func TestASTWithStruct(t *testing.T) {
+	testSQL(t,
+		testStructPerson{Name: "foo", Age: 12, Tags: []string{"a"}, Ignored: "x", private: "y"},
+		`STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>("foo", 12, ARRAY["a"])`,
+	)
+	testSQL(t,
+		&testStructPerson{Name: "foo"},
+		`STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>("foo", 0, ARRAY[])`,
+	)
+	testSQL(t,
+		(*testStructPerson)(nil),
+		`CAST(NULL AS STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>)`,
+	)
+	testSQL(t,
+		testStructNested{ID: 1, Person: testStructPerson{Name: "foo"}},
+		`STRUCT<ID INT64, Person STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>>(1, STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>("foo", 0, ARRAY[]))`,
+	)
+}
+
+func TestASTWithStructWithUnknownFieldType(t *testing.T) {
+	testSQL(t,
+		testStructWithInterface{A: nil, B: "foo"},
+		`STRUCT(NULL, "foo")`,
+	)
+	testSQL(t,
+		testStructWithInterface{A: (*string)(nil), B: "foo"},
+		`STRUCT(CAST(NULL AS STRING), "foo")`,
+	)
+}
+
+func TestASTWithStructSlice(t *testing.T) {
+	testSQL(t,
+		[]testStructPerson{{Name: "foo", Age: 1}, {Name: "bar", Age: 2}},
+		`ARRAY[STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>("foo", 1, ARRAY[]), STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>("bar", 2, ARRAY[])]`,
+	)
+	testSQL(t,
+		[]testStructPerson{},
+		`ARRAY<STRUCT<UserName STRING, Age INT64, Tags ARRAY<STRING>>>[]`,
+	)
+}
+
+func TestASTWithStructWithUnsupportedField(t *testing.T) {
+	_, err := internal.ToExpr(struct{ M map[string]string }{})
+	assert.Error(t, err, "map field")
+}
+
+type testStructOpaque struct {
+	coef int64
+	exp  int32
+}
+
+func TestASTWithStructWithoutColumns(t *testing.T) {
+	for _, val := range []interface{}{
+		testStructOpaque{1, 2},
+		&testStructOpaque{1, 2},
+		[]testStructOpaque{{1, 2}},
+		struct{}{},
+		struct {
+			A string `spanner:"-"`
+		}{},
+		struct{ O testStructOpaque }{},
+	} {
+		_, err := internal.ToExpr(val)
+		assert.Error(t, err, "%#v", val)
+	}
+	_, ok := internal.TypeOf(reflect.TypeOf(testStructOpaque{}))
+	assert.False(t, ok)
+	testSQL(t, (*testStructOpaque)(nil), `NULL`)
+}
+
+type testStructTimestamps struct {
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+type testStructAudit struct {
+	testStructTimestamps
+	UpdatedBy string
+}
+
+type testStructEmbedded struct {
+	ID int64
+	*testStructAudit
+	UpdatedAt string
+}
+
+type testStructAmbiguous struct {
+	testStructTimestamps
+	Other testStructTimestamps
+	*testStructTimestampsCopy
+}
+
+type testStructTimestampsCopy testStructTimestamps
+
+type testStructRecursive struct {
+	ID int64
+	*testStructRecursive
+}
+
+func TestStructFields(t *testing.T) {
+	fields, err := internal.StructFields(reflect.TypeOf(testStructEmbedded{}))
+	assert.Nil(t, err)
+	assert.Equal(t, []internal.StructField{
+		{Name: "ID", Index: []int{0}, Type: reflect.TypeOf(int64(0))},
+		{Name: "CreatedAt", Index: []int{1, 0, 0}, Type: reflect.TypeOf(int64(0))},
+		{Name: "UpdatedBy", Index: []int{1, 1}, Type: reflect.TypeOf("")},
+		{Name: "UpdatedAt", Index: []int{2}, Type: reflect.TypeOf("")},
+	}, fields)
+
+	v := reflect.ValueOf(testStructEmbedded{testStructAudit: &testStructAudit{UpdatedBy: "foo"}})
+	assert.Equal(t, "foo", fields[2].Value(v).Interface())
+	v = reflect.ValueOf(testStructEmbedded{})
+	assert.Equal(t, (*string)(nil), fields[2].Value(v).Interface())
+
+	fields, err = internal.StructFields(reflect.TypeOf(testStructRecursive{}))
+	assert.Nil(t, err)
+	assert.Equal(t, []internal.StructField{
+		{Name: "ID", Index: []int{0}, Type: reflect.TypeOf(int64(0))},
+	}, fields)
+}
+
+type testStructWithOptions struct {
+	ID        int64     `spanner:",readonly"`
+	Name      string    `spanner:"name,default"`
+	FullName  string    `spanner:"full_name,generated"`
+	UpdatedAt time.Time `spanner:"updated_at,commit_ts"`
+}
+
+func TestStructFieldsWithOptions(t *testing.T) {
+	fields, err := internal.StructFields(reflect.TypeOf(testStructWithOptions{}))
+	assert.Nil(t, err)
+	assert.Equal(t, []internal.StructField{
+		{Name: "ID", Index: []int{0}, Type: reflect.TypeOf(int64(0)), ReadOnly: true},
+		{Name: "name", Index: []int{1}, Type: reflect.TypeOf(""), Tagged: true, Default: true},
+		{Name: "full_name", Index: []int{2}, Type: reflect.TypeOf(""), Tagged: true, ReadOnly: true},
+		{Name: "updated_at", Index: []int{3}, Type: reflect.TypeOf(time.Time{}), Tagged: true, CommitTimestamp: true},
+	}, fields)
+
+	_, err = internal.StructFields(reflect.TypeOf(struct {
+		A string `spanner:"a,unknown"`
+	}{}))
+	assert.Error(t, err)
+}
+
+func TestStructFieldsWithAmbiguousColumns(t *testing.T) {
+	_, err := internal.StructFields(reflect.TypeOf(testStructAmbiguous{}))
+	assert.Error(t, err)
+	_, err = internal.StructFields(reflect.TypeOf(struct {
+		ID int64
+		Id int64
+	}{}))
+	assert.Error(t, err)
+}
+
+func TestASTWithEmbeddedStruct(t *testing.T) {
+	testSQL(t,
+		testStructAudit{testStructTimestamps: testStructTimestamps{CreatedAt: 1, UpdatedAt: 2}, UpdatedBy: "foo"},
+		`STRUCT<CreatedAt INT64, UpdatedAt INT64, UpdatedBy STRING>(1, 2, "foo")`,
+	)
+}
+
+type testStructRecursiveField struct {
+	ID   int64
+	Next *testStructRecursiveField
+}
+
+func TestASTWithRecursiveStruct(t *testing.T) {
+	_, ok := internal.TypeOf(reflect.TypeOf(testStructRecursiveField{}))
+	assert.False(t, ok)
+	testSQL(t,
+		testStructRecursiveField{ID: 1},
+		`STRUCT(1, NULL)`,
+	)
+	testSQL(t,
+		testStructRecursiveField{ID: 1, Next: &testStructRecursiveField{ID: 2}},
+		`STRUCT(1, STRUCT(2, NULL))`,
+	)
+	testSQL(t,
+		[]*testStructRecursiveField{nil},
+		`ARRAY[NULL]`,
+	)
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"math/big"
+	"reflect"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/MakeNowJust/memefish/pkg/ast"
+)
+
+var (
+	astExprType     = reflect.TypeOf((*ASTExpr)(nil)).Elem()
+	encoderType     = reflect.TypeOf((*spanner.Encoder)(nil)).Elem()
+	timeType        = reflect.TypeOf(time.Time{})
+	dateType        = reflect.TypeOf(civil.Date{})
+	ratType         = reflect.TypeOf(big.Rat{})
+	nullStringType  = reflect.TypeOf(spanner.NullString{})
+	nullInt64Type   = reflect.TypeOf(spanner.NullInt64{})
+	nullBoolType    = reflect.TypeOf(spanner.NullBool{})
+	nullFloat64Type = reflect.TypeOf(spanner.NullFloat64{})
+	nullTimeType    = reflect.TypeOf(spanner.NullTime{})
+	nullDateType    = reflect.TypeOf(spanner.NullDate{})
+	nullNumericType = reflect.TypeOf(spanner.NullNumeric{})
+)
+
+// TypeOf returns a SQL type that corresponds to the given Go type.
+// It returns false if the SQL type can't be determined statically.
+func TypeOf(t reflect.Type) (ast.Type, bool) {
+	return typeOf(t, map[reflect.Type]bool{})
+}
+
+// typeOf is the same as TypeOf except that it takes struct types that are being visited.
+// It returns false for recursive types because they don't have corresponding SQL types.
+func typeOf(t reflect.Type, visiting map[reflect.Type]bool) (ast.Type, bool) {
+	if t.Implements(astExprType) {
+		return nil, false
+	}
+	switch t {
+	case timeType, nullTimeType:
+		return simpleType(ast.TimestampTypeName), true
+	case dateType, nullDateType:
+		return simpleType(ast.DateTypeName), true
+	case ratType, nullNumericType:
+		return simpleType(ast.NumericTypeName), true
+	case nullStringType:
+		return simpleType(ast.StringTypeName), true
+	case nullInt64Type:
+		return simpleType(ast.Int64TypeName), true
+	case nullBoolType:
+		return simpleType(ast.BoolTypeName), true
+	case nullFloat64Type:
+		return simpleType(ast.Float64TypeName), true
+	}
+	// Values that are converted by ValueConverters or spanner.Encoder can be of any type.
+	if _, ok := lookupConverter(t); ok || implementsEncoder(t) {
+		return nil, false
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeOf(t.Elem(), visiting)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return simpleType(ast.Int64TypeName), true
+	case reflect.Float32:
+		return simpleType(Float32TypeName), true
+	case reflect.Float64:
+		return simpleType(ast.Float64TypeName), true
+	case reflect.String:
+		return simpleType(ast.StringTypeName), true
+	case reflect.Bool:
+		return simpleType(ast.BoolTypeName), true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return simpleType(ast.BytesTypeName), true
+		}
+		elem, ok := typeOf(t.Elem(), visiting)
+		if !ok {
+			return nil, false
+		}
+		if _, isArray := elem.(*ast.ArrayType); isArray {
+			// Spanner does not support arrays of arrays.
+			return nil, false
+		}
+		return &ast.ArrayType{Item: elem}, true
+	case reflect.Struct:
+		return structType(t, visiting)
+	default:
+		return nil, false
+	}
+}
+
+// isScalarStruct reports whether values of the struct type t are converted into scalar values rather than STRUCTs.
+func isScalarStruct(t reflect.Type) bool {
+	switch t {
+	case timeType, dateType, ratType, nullStringType, nullInt64Type, nullBoolType,
+		nullFloat64Type, nullTimeType, nullDateType, nullNumericType:
+		return true
+	}
+	if _, ok := lookupConverter(t); ok {
+		return true
+	}
+	return implementsEncoder(t) || t.Implements(astExprType)
+}
+
+// implementsEncoder reports whether values of t are encoded by spanner.Encoder.
+// Types whose pointer types implement spanner.Encoder are also encoded by it, because ToExpr encodes their copies.
+func implementsEncoder(t reflect.Type) bool {
+	return t.Implements(encoderType) || (t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(encoderType))
+}
+
+func simpleType(name ast.ScalarTypeName) *ast.SimpleType {
+	return &ast.SimpleType{Name: name}
+}
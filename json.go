@@ -0,0 +1,431 @@
+package memeduck
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/pkg/errors"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+// jsonCond is the JSON representation of WhereConds. See UnmarshalWhereCond for details.
+type jsonCond struct {
+	Op    string          `json:"op"`
+	LHS   *jsonOperand    `json:"lhs,omitempty"`
+	RHS   json.RawMessage `json:"rhs,omitempty"`
+	Arg   *jsonOperand    `json:"arg,omitempty"`
+	Min   *jsonOperand    `json:"min,omitempty"`
+	Max   *jsonOperand    `json:"max,omitempty"`
+	Conds []*jsonCond     `json:"conds,omitempty"`
+	Value *bool           `json:"value,omitempty"`
+}
+
+type jsonOperand struct {
+	Ident []string        `json:"ident,omitempty"`
+	Param string          `json:"param,omitempty"`
+	Type  string          `json:"type,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type jsonInConditionValue struct {
+	Unnest *jsonOperand `json:"unnest"`
+}
+
+const (
+	jsonOpIsNull     = "IS NULL"
+	jsonOpIsNotNull  = "IS NOT NULL"
+	jsonOpIn         = "IN"
+	jsonOpNotIn      = "NOT IN"
+	jsonOpBetween    = "BETWEEN"
+	jsonOpNotBetween = "NOT BETWEEN"
+	jsonOpBool       = "BOOL"
+)
+
+var jsonBinaryOps = map[string]BinaryOp{
+	string(EQ):       EQ,
+	string(NE):       NE,
+	string(LT):       LT,
+	string(GT):       GT,
+	string(LE):       LE,
+	string(GE):       GE,
+	string(LIKE):     LIKE,
+	string(NOT_LIKE): NOT_LIKE,
+}
+
+var jsonLogicalOps = map[string]logicalOp{
+	string(logicalOpAnd): logicalOpAnd,
+	string(logicalOpOr):  logicalOpOr,
+}
+
+// jsonCondMarshaler is a WhereCond that has JSON representation.
+type jsonCondMarshaler interface {
+	toJSONCond() (*jsonCond, error)
+}
+
+func marshalCond(cond WhereCond) ([]byte, error) {
+	c, err := toJSONCond(cond)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(c)
+}
+
+func toJSONCond(cond WhereCond) (*jsonCond, error) {
+	m, ok := cond.(jsonCondMarshaler)
+	if !ok {
+		return nil, errors.Errorf("%T can't be marshaled into JSON", cond)
+	}
+	return m.toJSONCond()
+}
+
+func toJSONOperand(v interface{}) (*jsonOperand, error) {
+	switch e := v.(type) {
+	case *IdentExpr:
+		if len(e.names) <= 0 {
+			return nil, errors.New("empty identifier")
+		}
+		return &jsonOperand{Ident: e.names}, nil
+	case *ParamExpr:
+		return &jsonOperand{Param: e.name}, nil
+	}
+	expr, err := internal.ToExpr(v)
+	if err != nil {
+		return nil, err
+	}
+	typ, value, err := internal.MarshalLiteral(expr)
+	if err != nil {
+		return nil, errors.WithMessage(err, "can't marshal operand into JSON")
+	}
+	return &jsonOperand{Type: typ, Value: value}, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *ExprCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *ExprCond) toJSONCond() (*jsonCond, error) {
+	lit, ok := c.expr.(*ast.BoolLiteral)
+	if !ok {
+		return nil, errors.Errorf("%s can't be marshaled into JSON", c.expr.SQL())
+	}
+	return &jsonCond{Op: jsonOpBool, Value: &lit.Value}, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *OpCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *OpCond) toJSONCond() (*jsonCond, error) {
+	lhs, err := toJSONOperand(c.lhs)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := toJSONOperand(c.rhs)
+	if err != nil {
+		return nil, err
+	}
+	rhsJSON, err := json.Marshal(rhs)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonCond{Op: string(c.op), LHS: lhs, RHS: rhsJSON}, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *NullCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *NullCond) toJSONCond() (*jsonCond, error) {
+	arg, err := toJSONOperand(c.arg)
+	if err != nil {
+		return nil, err
+	}
+	op := jsonOpIsNull
+	if c.not {
+		op = jsonOpIsNotNull
+	}
+	return &jsonCond{Op: op, Arg: arg}, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *InCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *InCond) toJSONCond() (*jsonCond, error) {
+	lhs, err := toJSONOperand(c.lhs)
+	if err != nil {
+		return nil, err
+	}
+	unnest, ok := c.rhs.(*UnnestInConditionValue)
+	if !ok {
+		return nil, errors.Errorf("%T can't be marshaled into JSON", c.rhs)
+	}
+	value, err := toJSONOperand(unnest.value)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := json.Marshal(&jsonInConditionValue{Unnest: value})
+	if err != nil {
+		return nil, err
+	}
+	op := jsonOpIn
+	if c.not {
+		op = jsonOpNotIn
+	}
+	return &jsonCond{Op: op, LHS: lhs, RHS: rhs}, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *BetweenCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *BetweenCond) toJSONCond() (*jsonCond, error) {
+	arg, err := toJSONOperand(c.arg)
+	if err != nil {
+		return nil, err
+	}
+	min, err := toJSONOperand(c.min)
+	if err != nil {
+		return nil, err
+	}
+	max, err := toJSONOperand(c.max)
+	if err != nil {
+		return nil, err
+	}
+	op := jsonOpBetween
+	if c.not {
+		op = jsonOpNotBetween
+	}
+	return &jsonCond{Op: op, Arg: arg, Min: min, Max: max}, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *LogicalOpCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *LogicalOpCond) toJSONCond() (*jsonCond, error) {
+	if isAbsentCond(c) {
+		// Marshal it as empty AND so that it doesn't filter anything after unmarshaling.
+		return &jsonCond{Op: string(logicalOpAnd)}, nil
+	}
+	conds := make([]*jsonCond, 0, len(c.conds))
+	for _, cond := range c.conds {
+		if isAbsentCond(cond) {
+			continue
+		}
+		jc, err := toJSONCond(cond)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, jc)
+	}
+	return &jsonCond{Op: string(c.op), Conds: conds}, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The conditions are marshaled as AND of equalities.
+func (c *MapCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *MapCond) toJSONCond() (*jsonCond, error) {
+	cond, err := c.toLogicalOpCond()
+	if err != nil {
+		return nil, err
+	}
+	return cond.toJSONCond()
+}
+
+// MarshalJSON implements json.Marshaler.
+// The conditions are marshaled as AND of equalities.
+func (c *StructCond) MarshalJSON() ([]byte, error) {
+	return marshalCond(c)
+}
+
+func (c *StructCond) toJSONCond() (*jsonCond, error) {
+	cond, err := c.toLogicalOpCond()
+	if err != nil {
+		return nil, err
+	}
+	return cond.toJSONCond()
+}
+
+// ColumnFilter reports whether the given identifier can be used in conditions decoded from JSON or parsed by ParseFilter.
+type ColumnFilter func(ident []string) bool
+
+// AllowColumns creates a ColumnFilter that only accepts the given column names.
+func AllowColumns(cols ...string) ColumnFilter {
+	allowed := make(map[string]bool, len(cols))
+	for _, col := range cols {
+		allowed[col] = true
+	}
+	return func(ident []string) bool {
+		return len(ident) == 1 && allowed[ident[0]]
+	}
+}
+
+// UnmarshalWhereCond decodes a WhereCond from its JSON representation, which can be obtained by json.Marshal.
+// Each identifier in data is checked by allow so that untrusted input can't refer to arbitrary columns.
+// allow must not be nil; pass a function that always returns true to accept all identifiers.
+//
+// A condition is represented as one of the following:
+//
+//	{"op": "=", "lhs": OPERAND, "rhs": OPERAND}             (also "!=", "<", ">", "<=", ">=", "LIKE", "NOT LIKE")
+//	{"op": "IS NULL", "arg": OPERAND}                       (also "IS NOT NULL")
+//	{"op": "IN", "lhs": OPERAND, "rhs": {"unnest": OPERAND}} (also "NOT IN")
+//	{"op": "BETWEEN", "arg": OPERAND, "min": OPERAND, "max": OPERAND} (also "NOT BETWEEN")
+//	{"op": "AND", "conds": [COND, ...]}                     (also "OR")
+//	{"op": "BOOL", "value": true}
+//
+// OPERAND is one of the following:
+//
+//	{"ident": ["name", ...]}
+//	{"param": "name"}
+//	{"type": "INT64", "value": "123"}
+//
+// The type of a value can be omitted. In that case, it is inferred from the JSON value.
+func UnmarshalWhereCond(data []byte, allow ColumnFilter) (WhereCond, error) {
+	if allow == nil {
+		return nil, errors.New("no ColumnFilter specified")
+	}
+	var c jsonCond
+	if err := decodeJSONStrict(data, &c); err != nil {
+		return nil, errors.Wrap(err, "invalid WHERE condition")
+	}
+	d := &jsonCondDecoder{allow: allow}
+	return d.cond(&c)
+}
+
+type jsonCondDecoder struct {
+	allow ColumnFilter
+}
+
+func (d *jsonCondDecoder) cond(c *jsonCond) (WhereCond, error) {
+	if c == nil {
+		return nil, errors.New("condition is null")
+	}
+	if op, ok := jsonBinaryOps[c.Op]; ok {
+		lhs, err := d.operand(c.LHS)
+		if err != nil {
+			return nil, err
+		}
+		var rhsOperand *jsonOperand
+		if err := decodeJSONStrict(c.RHS, &rhsOperand); err != nil {
+			return nil, errors.Wrapf(err, "invalid rhs of %s", c.Op)
+		}
+		rhs, err := d.operand(rhsOperand)
+		if err != nil {
+			return nil, err
+		}
+		return Op(lhs, op, rhs), nil
+	}
+	if op, ok := jsonLogicalOps[c.Op]; ok {
+		conds := make([]WhereCond, 0, len(c.Conds))
+		for _, jc := range c.Conds {
+			cond, err := d.cond(jc)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, cond)
+		}
+		return &LogicalOpCond{op: op, conds: conds}, nil
+	}
+	switch c.Op {
+	case jsonOpIsNull, jsonOpIsNotNull:
+		arg, err := d.operand(c.Arg)
+		if err != nil {
+			return nil, err
+		}
+		return &NullCond{arg: arg, not: c.Op == jsonOpIsNotNull}, nil
+	case jsonOpIn, jsonOpNotIn:
+		lhs, err := d.operand(c.LHS)
+		if err != nil {
+			return nil, err
+		}
+		var rhs jsonInConditionValue
+		if err := decodeJSONStrict(c.RHS, &rhs); err != nil {
+			return nil, errors.Wrapf(err, "invalid rhs of %s", c.Op)
+		}
+		value, err := d.operand(rhs.Unnest)
+		if err != nil {
+			return nil, err
+		}
+		return &InCond{lhs: lhs, rhs: Unnest(value), not: c.Op == jsonOpNotIn}, nil
+	case jsonOpBetween, jsonOpNotBetween:
+		arg, err := d.operand(c.Arg)
+		if err != nil {
+			return nil, err
+		}
+		min, err := d.operand(c.Min)
+		if err != nil {
+			return nil, err
+		}
+		max, err := d.operand(c.Max)
+		if err != nil {
+			return nil, err
+		}
+		return &BetweenCond{arg: arg, min: min, max: max, not: c.Op == jsonOpNotBetween}, nil
+	case jsonOpBool:
+		if c.Value == nil {
+			return nil, errors.New("BOOL requires value")
+		}
+		return Bool(*c.Value), nil
+	default:
+		return nil, errors.Errorf("unknown operator %q", c.Op)
+	}
+}
+
+func (d *jsonCondDecoder) operand(o *jsonOperand) (interface{}, error) {
+	if o == nil {
+		return nil, errors.New("operand is missing")
+	}
+	switch {
+	case o.Ident != nil && o.Param == "" && o.Type == "" && o.Value == nil:
+		if len(o.Ident) <= 0 {
+			return nil, errors.New("empty identifier")
+		}
+		if !d.allow(o.Ident) {
+			return nil, errors.Errorf("column %s is not allowed", strings.Join(o.Ident, "."))
+		}
+		return Ident(o.Ident...), nil
+	case o.Param != "" && o.Ident == nil && o.Type == "" && o.Value == nil:
+		return Param(o.Param), nil
+	case o.Value != nil && o.Ident == nil && o.Param == "":
+		lit := &literalExpr{typ: o.Type, value: o.Value}
+		if _, err := lit.ToASTExpr(); err != nil {
+			return nil, err
+		}
+		return lit, nil
+	default:
+		return nil, errors.New("operand must have either ident, param, or value")
+	}
+}
+
+// literalExpr is a literal decoded from JSON.
+// It keeps the JSON representation because AST nodes may be modified after they are built.
+type literalExpr struct {
+	typ   string
+	value json.RawMessage
+}
+
+func (e *literalExpr) ToASTExpr() (ast.Expr, error) {
+	return internal.UnmarshalLiteral(e.typ, e.value)
+}
+
+func decodeJSONStrict(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return errors.New("unexpected end of JSON input")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,189 @@
+package memeduck_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck"
+)
+
+func testJSONRoundTrip(t *testing.T, cond memeduck.WhereCond, expectedJSON, expectedSQL string) {
+	data, err := json.Marshal(cond)
+	assert.Nil(t, err, expectedSQL)
+	assert.JSONEq(t, expectedJSON, string(data))
+	decoded, err := memeduck.UnmarshalWhereCond(data, allowAllColumns)
+	assert.Nil(t, err, expectedSQL)
+	testWhere(t, decoded, expectedSQL)
+}
+
+func TestWhereCondJSON(t *testing.T) {
+	testJSONRoundTrip(t,
+		memeduck.Bool(true),
+		`{"op": "BOOL", "value": true}`,
+		`TRUE`,
+	)
+	testJSONRoundTrip(t,
+		memeduck.Eq(memeduck.Ident("a"), 1),
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"type": "INT64", "value": "1"}}`,
+		`a = 1`,
+	)
+	testJSONRoundTrip(t,
+		memeduck.NotLike(memeduck.Ident("a", "b"), memeduck.Param("p")),
+		`{"op": "NOT LIKE", "lhs": {"ident": ["a", "b"]}, "rhs": {"param": "p"}}`,
+		`a.b NOT LIKE @p`,
+	)
+	testJSONRoundTrip(t,
+		memeduck.IsNotNull(memeduck.Ident("a")),
+		`{"op": "IS NOT NULL", "arg": {"ident": ["a"]}}`,
+		`a IS NOT NULL`,
+	)
+	testJSONRoundTrip(t,
+		memeduck.NotIn(memeduck.Ident("a"), memeduck.Unnest([]string{"x", "y"})),
+		`{"op": "NOT IN", "lhs": {"ident": ["a"]}, "rhs": {"unnest": {"type": "ARRAY<STRING>", "value": ["x", "y"]}}}`,
+		`a NOT IN UNNEST(ARRAY<STRING>["x", "y"])`,
+	)
+	testJSONRoundTrip(t,
+		memeduck.Between(memeduck.Ident("a"), 1.5, math.Inf(1)),
+		`{"op": "BETWEEN", "arg": {"ident": ["a"]}, "min": {"type": "FLOAT64", "value": 1.5}, "max": {"type": "FLOAT64", "value": "Infinity"}}`,
+		`a BETWEEN 1.5e+00 AND +Inf`,
+	)
+	testJSONRoundTrip(t,
+		memeduck.Or(
+			memeduck.And(
+				memeduck.Eq(memeduck.Ident("a"), (*string)(nil)),
+				nil,
+			),
+			memeduck.Ne(memeduck.Ident("b"), nil),
+		),
+		`{"op": "OR", "conds": [
+			{"op": "AND", "conds": [{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"type": "STRING", "value": null}}]},
+			{"op": "!=", "lhs": {"ident": ["b"]}, "rhs": {"value": null}}
+		]}`,
+		`a = CAST(NULL AS STRING) OR b != NULL`,
+	)
+	testJSONRoundTrip(t,
+		memeduck.And(
+			memeduck.Eq(memeduck.Ident("a"), 1),
+			memeduck.Or(nil, memeduck.And(nil)),
+		),
+		`{"op": "AND", "conds": [{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"type": "INT64", "value": "1"}}]}`,
+		`a = 1`,
+	)
+	testJSONRoundTrip(t, memeduck.Or(nil), `{"op": "AND"}`, `TRUE`)
+	testJSONRoundTrip(t,
+		memeduck.EqMap(map[string]interface{}{"b": []byte("hoge"), "a": nil}),
+		`{"op": "AND", "conds": [
+			{"op": "IS NULL", "arg": {"ident": ["a"]}},
+			{"op": "=", "lhs": {"ident": ["b"]}, "rhs": {"type": "BYTES", "value": "aG9nZQ=="}}
+		]}`,
+		`a IS NULL AND b = B"hoge"`,
+	)
+}
+
+func TestWhereCondJSONWithTypes(t *testing.T) {
+	testCases := []struct {
+		val          interface{}
+		expectedJSON string
+		expectedSQL  string
+	}{
+		{true, `{"type": "BOOL", "value": true}`, `TRUE`},
+		{float32(1.5), `{"type": "FLOAT32", "value": 1.5}`, `CAST(1.5e+00 AS FLOAT32)`},
+		{math.NaN(), `{"type": "FLOAT64", "value": "NaN"}`, `NaN`},
+		{time.Date(2021, 1, 2, 3, 4, 5, 6, time.UTC), `{"type": "TIMESTAMP", "value": "2021-01-02T03:04:05.000000006Z"}`, `TIMESTAMP "2021-01-02T03:04:05.000000006Z"`},
+		{civil.Date{Year: 2021, Month: 1, Day: 2}, `{"type": "DATE", "value": "2021-01-02"}`, `DATE "2021-01-02"`},
+		{big.NewRat(-3, 2), `{"type": "NUMERIC", "value": "-1.5"}`, `NUMERIC "-1.5"`},
+		{int64(math.MaxInt64), `{"type": "INT64", "value": "9223372036854775807"}`, `9223372036854775807`},
+		{[]int64{}, `{"type": "ARRAY<INT64>", "value": []}`, `ARRAY<INT64>[]`},
+		{[]*string{nil}, `{"type": "ARRAY<STRING>", "value": [null]}`, `ARRAY<STRING>[NULL]`},
+		{(*[]int64)(nil), `{"type": "ARRAY<INT64>", "value": null}`, `CAST(NULL AS ARRAY<INT64>)`},
+	}
+	for _, tc := range testCases {
+		testJSONRoundTrip(t,
+			memeduck.Eq(memeduck.Ident("a"), tc.val),
+			`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": `+tc.expectedJSON+`}`,
+			`a = `+tc.expectedSQL,
+		)
+	}
+}
+
+func TestUnmarshalWhereCondWithInferredTypes(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{`1`, `a = 1`},
+		{`1.5`, `a = 1.5e+00`},
+		{`"hoge"`, `a = "hoge"`},
+		{`false`, `a = FALSE`},
+		{`null`, `a = NULL`},
+		{`[1, null]`, `a = ARRAY[1, NULL]`},
+	}
+	for _, tc := range testCases {
+		cond, err := memeduck.UnmarshalWhereCond([]byte(`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"value": `+tc.value+`}}`), allowAllColumns)
+		assert.Nil(t, err, tc.value)
+		testWhere(t, cond, tc.expected)
+	}
+}
+
+func TestUnmarshalWhereCondWithAllowList(t *testing.T) {
+	allow := memeduck.AllowColumns("name", "age")
+	cond, err := memeduck.UnmarshalWhereCond([]byte(`{"op": "AND", "conds": [
+		{"op": ">=", "lhs": {"ident": ["age"]}, "rhs": {"value": 18}},
+		{"op": "LIKE", "lhs": {"ident": ["name"]}, "rhs": {"value": "a%"}}
+	]}`), allow)
+	assert.Nil(t, err)
+	testWhere(t, cond, `age >= 18 AND name LIKE "a%"`)
+
+	for _, data := range []string{
+		`{"op": "=", "lhs": {"ident": ["password"]}, "rhs": {"value": "x"}}`,
+		`{"op": "=", "lhs": {"value": "x"}, "rhs": {"ident": ["password"]}}`,
+		`{"op": "IS NULL", "arg": {"ident": ["user", "name"]}}`,
+		`{"op": "OR", "conds": [{"op": "BOOL", "value": false}, {"op": "IS NULL", "arg": {"ident": ["password"]}}]}`,
+	} {
+		_, err := memeduck.UnmarshalWhereCond([]byte(data), allow)
+		assert.Error(t, err, data)
+	}
+	_, err = memeduck.UnmarshalWhereCond([]byte(`{"op": "IS NULL", "arg": {"ident": ["age"]}}`), nil)
+	assert.Error(t, err)
+}
+
+func TestUnmarshalWhereCondWithInvalidInput(t *testing.T) {
+	for _, data := range []string{
+		``,
+		`null`,
+		`{"op": "XOR", "conds": []}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {}}`,
+		`{"op": "=", "lhs": {"ident": []}, "rhs": {"value": 1}}`,
+		`{"op": "=", "lhs": {"ident": ["a"], "param": "p"}, "rhs": {"value": 1}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"value": 1, "unknown": 2}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"type": "INT64", "value": "x"}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"type": "INT64", "value": 1.5}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"type": "UNKNOWN", "value": 1}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"type": "NUMERIC", "value": "0.0000000001"}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"value": {"x": 1}}}`,
+		`{"op": "=", "lhs": {"ident": ["a"]}, "rhs": {"value": [[1]]}}`,
+		`{"op": "IN", "lhs": {"ident": ["a"]}, "rhs": {"ident": ["b"]}}`,
+		`{"op": "BETWEEN", "arg": {"ident": ["a"]}, "min": {"value": 1}}`,
+		`{"op": "BOOL"}`,
+		`{"op": "AND", "conds": [null]}`,
+		`{"op": "AND", "unknown": []}`,
+	} {
+		_, err := memeduck.UnmarshalWhereCond([]byte(data), allowAllColumns)
+		assert.Error(t, err, data)
+	}
+}
+
+func TestMarshalWhereCondWithUnsupportedExprs(t *testing.T) {
+	sub := memeduck.ScalarSubQuery(memeduck.Select("hoge", []string{"a"}))
+	_, err := json.Marshal(memeduck.Eq(memeduck.Ident("a"), sub))
+	assert.Error(t, err)
+	_, err = json.Marshal(memeduck.Eq(memeduck.Ident("a"), struct{ A int64 }{1}))
+	assert.Error(t, err)
+}
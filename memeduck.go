@@ -2,8 +2,10 @@ package memeduck
 
 import (
 	"reflect"
+	"sort"
 	"strings"
 
+	"cloud.google.com/go/spanner"
 	"github.com/MakeNowJust/memefish/pkg/ast"
 	"github.com/pkg/errors"
 
@@ -21,6 +23,8 @@ type SelectStmt struct {
 	offset     *int
 	asStruct   bool
 	subQueries []SubQuery
+	untyped    bool
+	params     []binding
 }
 
 type ordering struct {
@@ -63,6 +67,22 @@ func (s *SelectStmt) SubQuery(queries ...SubQuery) *SelectStmt {
 	return &t
 }
 
+// Params binds values to query parameters used in the SELECT statement.
+// Statement returns them as its Params, and fails if any parameter is unbound, bound to conflicting values, or bound but not used.
+func (s *SelectStmt) Params(params map[string]interface{}) *SelectStmt {
+	var t = *s
+	t.params = appendBindings(t.params, params)
+	return &t
+}
+
+// Untyped makes the SELECT statement use untyped NULLs and empty arrays (e.g. `NULL` and `ARRAY[]`)
+// instead of typed ones (e.g. `CAST(NULL AS STRING)` and `ARRAY<STRING>[]`).
+func (s *SelectStmt) Untyped() *SelectStmt {
+	var t = *s
+	t.untyped = true
+	return &t
+}
+
 // Where appends given codintional expressions to the SELECT statement.
 func (s *SelectStmt) Where(conds ...WhereCond) *SelectStmt {
 	var t = *s
@@ -109,13 +129,38 @@ func (s *SelectStmt) SQL() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return stmt.SQL(), nil
+	return toSQL(stmt, s.untyped)
+}
+
+// Statement returns the SELECT statement as spanner.Statement.
+// Unlike SQL, literal values in the statement are replaced with query parameters (@p1, @p2, ...)
+// and the values are stored in Params.
+func (s *SelectStmt) Statement() (spanner.Statement, error) {
+	stmt, err := s.toAST()
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	return toStatement(stmt, s.untyped, s.boundParams())
+}
+
+// boundParams returns parameters bound to the SELECT statement and its sub queries.
+func (s *SelectStmt) boundParams() []binding {
+	params := append([]binding{}, s.params...)
+	for _, q := range s.subQueries {
+		switch q := q.(type) {
+		case *ScalarSubQueryStmt:
+			params = append(params, q.query.boundParams()...)
+		case *ArraySubQueryStmt:
+			params = append(params, q.query.boundParams()...)
+		}
+	}
+	return params
 }
 
 func (s *SelectStmt) toAST() (*ast.Select, error) {
 	var err error
 	var where *ast.Where = nil
-	if len(s.conds) > 0 {
+	if !isEmptyConds(s.conds) {
 		where, err = And(s.conds...).ToASTWhere()
 		if err != nil {
 			return nil, err
@@ -194,11 +239,29 @@ func (s *SelectStmt) toAST() (*ast.Select, error) {
 	}, nil
 }
 
+// DefaultValue is the type of Default.
+type DefaultValue struct{}
+
+// Default is converted into DEFAULT keyword when it is used as a value in VALUES clause of INSERT statements
+// or SET clause of UPDATE statements, so that the column is set to its default value.
+var Default = DefaultValue{}
+
+func (DefaultValue) ToASTExpr() (ast.Expr, error) {
+	return nil, errors.New("DEFAULT can only be used as a value of INSERT or UPDATE statements")
+}
+
+// ErrNoWhereClause is returned when an UPDATE or DELETE statement has neither WHERE clause nor AllRows.
+var ErrNoWhereClause = errors.New("no WHERE clause is specified")
+
 // UpdateStmt builds UPDATE statements.
 type UpdateStmt struct {
-	table string
-	items []*updateItem
-	conds []WhereCond
+	table   string
+	items   []*updateItem
+	conds   []WhereCond
+	allRows bool
+	ret     *returning
+	untyped bool
+	params  []binding
 }
 
 type updateItem struct {
@@ -215,6 +278,10 @@ func (i *updateItem) toASTUpdateItem() (*ast.UpdateItem, error) {
 	for _, name := range i.ident.names {
 		path = append(path, &ast.Ident{Name: name})
 	}
+	if _, ok := i.value.(DefaultValue); ok {
+		// internal.DML renders this as `path = DEFAULT`.
+		return &ast.UpdateItem{Path: path}, nil
+	}
 	expr, err := internal.ToExpr(i.value)
 	if err != nil {
 		return nil, err
@@ -242,6 +309,30 @@ func (s *UpdateStmt) Set(id *IdentExpr, value interface{}) *UpdateStmt {
 	return &t
 }
 
+// Params binds values to query parameters used in the UPDATE statement.
+// Statement returns them as its Params, and fails if any parameter is unbound, bound to conflicting values, or bound but not used.
+func (s *UpdateStmt) Params(params map[string]interface{}) *UpdateStmt {
+	var t = *s
+	t.params = appendBindings(t.params, params)
+	return &t
+}
+
+// Untyped makes the UPDATE statement use untyped NULLs and empty arrays (e.g. `NULL` and `ARRAY[]`)
+// instead of typed ones (e.g. `CAST(NULL AS STRING)` and `ARRAY<STRING>[]`).
+func (s *UpdateStmt) Untyped() *UpdateStmt {
+	var t = *s
+	t.untyped = true
+	return &t
+}
+
+// AllRows explicitly allows the UPDATE statement to update all rows in the table.
+// If no conditions are given by Where, the statement has `WHERE TRUE` clause.
+func (s *UpdateStmt) AllRows() *UpdateStmt {
+	var t = *s
+	t.allRows = true
+	return &t
+}
+
 // Where adds a WHERE clause to the UPDATE statement.
 func (s *UpdateStmt) Where(conds ...WhereCond) *UpdateStmt {
 	var t = *s
@@ -249,12 +340,48 @@ func (s *UpdateStmt) Where(conds ...WhereCond) *UpdateStmt {
 	return &t
 }
 
+// ThenReturn adds a THEN RETURN clause to the UPDATE statement, which returns the given items of affected rows.
+// Each item is either a column name, "*", a SubQuery, or a value that can be converted into SQL expression like Ident.
+// It replaces existing THEN RETURN clauses.
+func (s *UpdateStmt) ThenReturn(items ...interface{}) *UpdateStmt {
+	var t = *s
+	t.ret = &returning{items: items}
+	return &t
+}
+
+// ThenReturnWithAction is the same as ThenReturn except that it also returns the action performed on each row
+// (`THEN RETURN WITH ACTION AS alias ...`). If alias is empty, the action is returned as ACTION column.
+func (s *UpdateStmt) ThenReturnWithAction(alias string, items ...interface{}) *UpdateStmt {
+	var t = *s
+	t.ret = &returning{withAction: true, actionAlias: alias, items: items}
+	return &t
+}
+
 func (s *UpdateStmt) SQL() (string, error) {
-	stmt, err := s.toAST()
+	stmt, err := s.toDML()
 	if err != nil {
 		return "", err
 	}
-	return stmt.SQL(), nil
+	return toSQL(stmt, s.untyped)
+}
+
+// Statement returns the UPDATE statement as spanner.Statement.
+// Unlike SQL, literal values in the statement are replaced with query parameters (@p1, @p2, ...)
+// and the values are stored in Params.
+func (s *UpdateStmt) Statement() (spanner.Statement, error) {
+	stmt, err := s.toDML()
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	return toStatement(stmt, s.untyped, append(append([]binding{}, s.params...), s.ret.boundParams()...))
+}
+
+func (s *UpdateStmt) toDML() (*internal.DML, error) {
+	stmt, err := s.toAST()
+	if err != nil {
+		return nil, err
+	}
+	return s.ret.toDML(stmt, internal.InsertModeDefault)
 }
 
 func (s *UpdateStmt) toAST() (*ast.Update, error) {
@@ -270,6 +397,10 @@ func (s *UpdateStmt) toAST() (*ast.Update, error) {
 		items = append(items, astItem)
 	}
 
+	// Spanner requires WHERE clause to prevent updating all rows by accident.
+	if isEmptyConds(s.conds) && !s.allRows {
+		return nil, ErrNoWhereClause
+	}
 	cond, err := And(s.conds...).ToASTWhere()
 	if err != nil {
 		return nil, err
@@ -283,8 +414,12 @@ func (s *UpdateStmt) toAST() (*ast.Update, error) {
 
 // DeleteStmt builds DELETE statements.
 type DeleteStmt struct {
-	table string
-	conds []WhereCond
+	table   string
+	conds   []WhereCond
+	allRows bool
+	ret     *returning
+	untyped bool
+	params  []binding
 }
 
 // Delete creates a new DeleteStmt with given table name.
@@ -294,6 +429,30 @@ func Delete(table string) *DeleteStmt {
 	}
 }
 
+// Params binds values to query parameters used in the DELETE statement.
+// Statement returns them as its Params, and fails if any parameter is unbound, bound to conflicting values, or bound but not used.
+func (s *DeleteStmt) Params(params map[string]interface{}) *DeleteStmt {
+	var t = *s
+	t.params = appendBindings(t.params, params)
+	return &t
+}
+
+// Untyped makes the DELETE statement use untyped NULLs and empty arrays (e.g. `NULL` and `ARRAY[]`)
+// instead of typed ones (e.g. `CAST(NULL AS STRING)` and `ARRAY<STRING>[]`).
+func (s *DeleteStmt) Untyped() *DeleteStmt {
+	var t = *s
+	t.untyped = true
+	return &t
+}
+
+// AllRows explicitly allows the DELETE statement to delete all rows in the table.
+// If no conditions are given by Where, the statement has `WHERE TRUE` clause.
+func (s *DeleteStmt) AllRows() *DeleteStmt {
+	var t = *s
+	t.allRows = true
+	return &t
+}
+
 // Where appends given conditional expressions to the DELETE statement.
 func (s *DeleteStmt) Where(conds ...WhereCond) *DeleteStmt {
 	var t = *s
@@ -301,15 +460,55 @@ func (s *DeleteStmt) Where(conds ...WhereCond) *DeleteStmt {
 	return &t
 }
 
+// ThenReturn adds a THEN RETURN clause to the DELETE statement, which returns the given items of affected rows.
+// Each item is either a column name, "*", a SubQuery, or a value that can be converted into SQL expression like Ident.
+// It replaces existing THEN RETURN clauses.
+func (s *DeleteStmt) ThenReturn(items ...interface{}) *DeleteStmt {
+	var t = *s
+	t.ret = &returning{items: items}
+	return &t
+}
+
+// ThenReturnWithAction is the same as ThenReturn except that it also returns the action performed on each row
+// (`THEN RETURN WITH ACTION AS alias ...`). If alias is empty, the action is returned as ACTION column.
+func (s *DeleteStmt) ThenReturnWithAction(alias string, items ...interface{}) *DeleteStmt {
+	var t = *s
+	t.ret = &returning{withAction: true, actionAlias: alias, items: items}
+	return &t
+}
+
 func (s *DeleteStmt) SQL() (string, error) {
-	stmt, err := s.toAST()
+	stmt, err := s.toDML()
 	if err != nil {
 		return "", err
 	}
-	return stmt.SQL(), nil
+	return toSQL(stmt, s.untyped)
+}
+
+// Statement returns the DELETE statement as spanner.Statement.
+// Unlike SQL, literal values in the statement are replaced with query parameters (@p1, @p2, ...)
+// and the values are stored in Params.
+func (s *DeleteStmt) Statement() (spanner.Statement, error) {
+	stmt, err := s.toDML()
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	return toStatement(stmt, s.untyped, append(append([]binding{}, s.params...), s.ret.boundParams()...))
+}
+
+func (s *DeleteStmt) toDML() (*internal.DML, error) {
+	stmt, err := s.toAST()
+	if err != nil {
+		return nil, err
+	}
+	return s.ret.toDML(stmt, internal.InsertModeDefault)
 }
 
 func (s *DeleteStmt) toAST() (*ast.Delete, error) {
+	// Spanner requires WHERE clause to prevent deleting all rows by accident.
+	if isEmptyConds(s.conds) && !s.allRows {
+		return nil, ErrNoWhereClause
+	}
 	cond, err := And(s.conds...).ToASTWhere()
 	if err != nil {
 		return nil, err
@@ -322,12 +521,18 @@ func (s *DeleteStmt) toAST() (*ast.Delete, error) {
 
 // InsertStmt builds INSERT statements.
 type InsertStmt struct {
-	table  string
-	cols   []string
-	values interface{}
+	table   string
+	cols    []string
+	values  interface{}
+	query   *SelectStmt
+	mode    internal.InsertMode
+	ret     *returning
+	untyped bool
+	params  []binding
 }
 
 // Insert creates a new InsertStmt with given table name. and column names.
+// If cols is nil, column names are derived from the struct type of the rows given to Values.
 func Insert(table string, cols []string) *InsertStmt {
 	return &InsertStmt{
 		table: table,
@@ -335,44 +540,144 @@ func Insert(table string, cols []string) *InsertStmt {
 	}
 }
 
+// InsertStruct creates a new InsertStmt that inserts rows, which is a slice of structs or pointers to structs.
+// Column names are derived from the fields of the struct type in declaration order, in the same way as Values.
+//
+// The `spanner` tag of each field can have options after the column name:
+//
+//	`spanner:"UpdatedAt,commit_ts"` writes PENDING_COMMIT_TIMESTAMP() instead of the field.
+//	`spanner:"FullName,readonly"` (or `generated`) never writes the column.
+//	`spanner:"Score,default"` writes DEFAULT if the field has zero value.
+func InsertStruct(table string, rows interface{}) *InsertStmt {
+	return Insert(table, nil).Values(rows)
+}
+
 // Values returns an InsertStmt with its values set to given ones.
 // It replaces existing values.
+// Each row is either a slice of values, a struct, or a map from column names to values.
 func (s *InsertStmt) Values(values interface{}) *InsertStmt {
-	return &InsertStmt{
-		table:  s.table,
-		cols:   s.cols,
-		values: values,
-	}
+	var t = *s
+	t.values = values
+	t.query = nil
+	return &t
+}
+
+// Select returns an InsertStmt that inserts rows returned by the given query (`INSERT INTO ... SELECT ...`).
+// It replaces existing values. The query must not be SELECT AS STRUCT.
+func (s *InsertStmt) Select(query *SelectStmt) *InsertStmt {
+	var t = *s
+	t.values = nil
+	t.query = query
+	return &t
+}
+
+// Params binds values to query parameters used in the INSERT statement.
+// Statement returns them as its Params, and fails if any parameter is unbound, bound to conflicting values, or bound but not used.
+func (s *InsertStmt) Params(params map[string]interface{}) *InsertStmt {
+	var t = *s
+	t.params = appendBindings(t.params, params)
+	return &t
+}
+
+// Untyped makes the INSERT statement use untyped NULLs and empty arrays (e.g. `NULL` and `ARRAY[]`)
+// instead of typed ones (e.g. `CAST(NULL AS STRING)` and `ARRAY<STRING>[]`).
+func (s *InsertStmt) Untyped() *InsertStmt {
+	var t = *s
+	t.untyped = true
+	return &t
+}
+
+// OrUpdate makes the statement `INSERT OR UPDATE`, which updates existing rows instead of failing.
+// It replaces OrIgnore.
+func (s *InsertStmt) OrUpdate() *InsertStmt {
+	var t = *s
+	t.mode = internal.InsertModeUpdate
+	return &t
+}
+
+// OrIgnore makes the statement `INSERT OR IGNORE`, which skips existing rows instead of failing.
+// It replaces OrUpdate.
+func (s *InsertStmt) OrIgnore() *InsertStmt {
+	var t = *s
+	t.mode = internal.InsertModeIgnore
+	return &t
+}
+
+// ThenReturn adds a THEN RETURN clause to the INSERT statement, which returns the given items of affected rows.
+// Each item is either a column name, "*", a SubQuery, or a value that can be converted into SQL expression like Ident.
+// It replaces existing THEN RETURN clauses.
+func (s *InsertStmt) ThenReturn(items ...interface{}) *InsertStmt {
+	var t = *s
+	t.ret = &returning{items: items}
+	return &t
+}
+
+// ThenReturnWithAction is the same as ThenReturn except that it also returns the action performed on each row
+// (`THEN RETURN WITH ACTION AS alias ...`). If alias is empty, the action is returned as ACTION column.
+func (s *InsertStmt) ThenReturnWithAction(alias string, items ...interface{}) *InsertStmt {
+	var t = *s
+	t.ret = &returning{withAction: true, actionAlias: alias, items: items}
+	return &t
 }
 
 func (is *InsertStmt) SQL() (string, error) {
-	stmt, err := is.toAST()
+	stmt, err := is.toDML()
 	if err != nil {
 		return "", err
 	}
-	return stmt.SQL(), nil
+	return toSQL(stmt, is.untyped)
+}
+
+// Statement returns the INSERT statement as spanner.Statement.
+// Unlike SQL, literal values in the statement are replaced with query parameters (@p1, @p2, ...)
+// and the values are stored in Params.
+func (is *InsertStmt) Statement() (spanner.Statement, error) {
+	stmt, err := is.toDML()
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	return toStatement(stmt, is.untyped, is.boundParams())
+}
+
+// boundParams returns parameters bound to the INSERT statement, its query, and its THEN RETURN clause.
+func (s *InsertStmt) boundParams() []binding {
+	params := append([]binding{}, s.params...)
+	if s.query != nil {
+		params = append(params, s.query.boundParams()...)
+	}
+	return append(params, s.ret.boundParams()...)
+}
+
+func (s *InsertStmt) toDML() (*internal.DML, error) {
+	stmt, err := s.toAST()
+	if err != nil {
+		return nil, err
+	}
+	return s.ret.toDML(stmt, s.mode)
 }
 
 func (s *InsertStmt) toAST() (*ast.Insert, error) {
+	s, err := s.withColumns()
+	if err != nil {
+		return nil, err
+	}
 	cols := make([]*ast.Ident, 0, len(s.cols))
 	for _, name := range s.cols {
 		cols = append(cols, &ast.Ident{Name: name})
 	}
-	if s.values == nil {
-		return nil, errors.New("neither VALUES nor SELECT specified")
-	}
-	// TODO: support SELECT
 	var input ast.InsertInput
-	var err error
-	rowsV := reflect.ValueOf(s.values)
-	if rowsV.Type().Kind() == reflect.Slice {
+	if s.query != nil {
+		input, err = s.queryToInsertInput()
+	} else if s.values == nil {
+		return nil, errors.New("neither VALUES nor SELECT specified")
+	} else if rowsV := reflect.ValueOf(s.values); rowsV.Type().Kind() == reflect.Slice {
 		input, err = s.sliceToInsertInput(rowsV)
-		if err != nil {
-			return nil, err
-		}
 	} else {
 		return nil, errors.Errorf("can't create InsertInput")
 	}
+	if err != nil {
+		return nil, err
+	}
 	return &ast.Insert{
 		TableName: &ast.Ident{Name: s.table},
 		Columns:   cols,
@@ -380,6 +685,71 @@ func (s *InsertStmt) toAST() (*ast.Insert, error) {
 	}, nil
 }
 
+// withColumns returns an InsertStmt whose column names are inferred from its values if they are not specified.
+func (s *InsertStmt) withColumns() (*InsertStmt, error) {
+	if s.cols != nil || s.values == nil {
+		return s, nil
+	}
+	var t = *s
+	cols, err := inferColumns(s.values)
+	if err != nil {
+		return nil, err
+	}
+	t.cols = cols
+	return &t, nil
+}
+
+// inferColumns returns column names that correspond to the fields of the struct type of rows' elements.
+func inferColumns(rows interface{}) ([]string, error) {
+	rowsT := reflect.TypeOf(rows)
+	if rowsT.Kind() != reflect.Slice {
+		return nil, errors.Errorf("can't infer columns from %s", rowsT.String())
+	}
+	return inferRowColumns(rowsT.Elem())
+}
+
+// inferRowColumns returns column names that correspond to the fields of rowT, which is a struct or a pointer to it.
+// Read-only fields are excluded.
+func inferRowColumns(rowT reflect.Type) ([]string, error) {
+	if rowT.Kind() == reflect.Ptr {
+		rowT = rowT.Elem()
+	}
+	if rowT.Kind() != reflect.Struct {
+		return nil, errors.Errorf("can't infer columns from %s", rowT.String())
+	}
+	fields, err := internal.StructFields(rowT)
+	if err != nil {
+		return nil, err
+	}
+	var cols []string
+	for _, f := range fields {
+		if !f.ReadOnly {
+			cols = append(cols, f.Name)
+		}
+	}
+	if len(cols) <= 0 {
+		return nil, errors.Errorf("type %s has no columns", rowT.String())
+	}
+	return cols, nil
+}
+
+func (s *InsertStmt) queryToInsertInput() (ast.InsertInput, error) {
+	query, err := s.query.toAST()
+	if err != nil {
+		return nil, err
+	}
+	if query.AsStruct {
+		// A STRUCT value can't be inserted into a table since Spanner doesn't have STRUCT columns.
+		return nil, errors.New("SELECT AS STRUCT can't be used in INSERT")
+	}
+	if len(query.Results) != len(s.cols) {
+		return nil, errors.Errorf("number of selected columns (%d) does not match number of columns to insert (%d)", len(query.Results), len(s.cols))
+	}
+	return &ast.SubQueryInput{
+		Query: query,
+	}, nil
+}
+
 func (s *InsertStmt) sliceToInsertInput(rowsV reflect.Value) (ast.InsertInput, error) {
 	input := &ast.ValuesInput{}
 	if rowsV.Len() <= 0 {
@@ -407,21 +777,57 @@ func (s *InsertStmt) toValuesRow(val interface{}) (*ast.ValuesRow, error) {
 		if valV.Type().Elem().Kind() == reflect.Struct {
 			return s.structToValuesRow(valV.Elem())
 		}
-		return nil, errors.Errorf("%s is neither struct nor slice", valV.Type().String())
+		return nil, errors.Errorf("%s is neither struct, map nor slice", valV.Type().String())
+	case reflect.Map:
+		if valV.Type().Key().Kind() == reflect.String {
+			return s.mapToValuesRow(valV)
+		}
+		return nil, errors.Errorf("%s does not have string keys", valV.Type().String())
 	default:
-		return nil, errors.Errorf("%s is neither struct nor slice", valV.Type().String())
+		return nil, errors.Errorf("%s is neither struct, map nor slice", valV.Type().String())
 	}
 }
 
+// The type of valV is guaranteed to be map with string keys here.
+func (s *InsertStmt) mapToValuesRow(valV reflect.Value) (*ast.ValuesRow, error) {
+	row := &ast.ValuesRow{}
+	for _, colName := range s.cols {
+		v := valV.MapIndex(reflect.ValueOf(colName).Convert(valV.Type().Key()))
+		if !v.IsValid() {
+			return nil, errors.Errorf("row does not have column %s", colName)
+		}
+		expr, err := toDefaultExpr(v.Interface())
+		if err != nil {
+			return nil, errors.WithMessagef(err, "at column %s", colName)
+		}
+		row.Exprs = append(row.Exprs, expr)
+	}
+	cols := make(map[string]bool, len(s.cols))
+	for _, colName := range s.cols {
+		cols[colName] = true
+	}
+	if valV.Len() > len(cols) {
+		keys := make([]string, 0, valV.Len())
+		for _, k := range valV.MapKeys() {
+			if !cols[k.String()] {
+				keys = append(keys, k.String())
+			}
+		}
+		sort.Strings(keys)
+		return nil, errors.Errorf("row has extraneous columns %s", strings.Join(keys, ", "))
+	}
+	return row, nil
+}
+
 // The type of valV is guaranteed to be slice here.
 func (s *InsertStmt) sliceToValuesRow(valV reflect.Value) (*ast.ValuesRow, error) {
 	row := &ast.ValuesRow{}
 	for i := 0; i < valV.Len(); i++ {
-		expr, err := internal.ToExpr(valV.Index(i).Interface())
+		expr, err := toDefaultExpr(valV.Index(i).Interface())
 		if err != nil {
 			return nil, err
 		}
-		row.Exprs = append(row.Exprs, &ast.DefaultExpr{Expr: expr})
+		row.Exprs = append(row.Exprs, expr)
 	}
 	return row, nil
 }
@@ -430,34 +836,57 @@ func (s *InsertStmt) sliceToValuesRow(valV reflect.Value) (*ast.ValuesRow, error
 func (s *InsertStmt) structToValuesRow(valV reflect.Value) (*ast.ValuesRow, error) {
 	row := &ast.ValuesRow{}
 	valT := valV.Type()
-	numField := valT.NumField()
+	fields, err := internal.StructFields(valT)
+	if err != nil {
+		return nil, err
+	}
 	for _, colName := range s.cols {
-		colFound := false
-		for i := 0; i < numField; i++ {
-			ft := valT.Field(i)
-			if !columnNameMatches(&ft, colName) {
-				continue
-			}
-			colFound = true
-			expr, err := internal.ToExpr(valV.Field(i).Interface())
-			if err != nil {
-				return nil, err
-			}
-			row.Exprs = append(row.Exprs, &ast.DefaultExpr{Expr: expr})
-		}
-		if !colFound {
+		f, ok := findStructField(fields, colName)
+		if !ok {
 			return nil, errors.Errorf("type %s does not have column %s", valT.String(), colName)
 		}
+		expr, err := structFieldToDefaultExpr(f, valV)
+		if err != nil {
+			return nil, err
+		}
+		row.Exprs = append(row.Exprs, expr)
 	}
 	return row, nil
 }
 
-func columnNameMatches(field *reflect.StructField, colName string) bool {
-	tag := field.Tag.Get("spanner")
-	if tag == "" {
-		return strings.EqualFold(field.Name, colName)
-	} else if tag == "-" {
-		return false
+// structFieldToDefaultExpr converts the field f of valV into a value to be written, according to the options of f.
+func structFieldToDefaultExpr(f *internal.StructField, valV reflect.Value) (*ast.DefaultExpr, error) {
+	if f.ReadOnly {
+		return nil, errors.Errorf("column %s is read-only", f.Name)
+	}
+	if f.CommitTimestamp {
+		return &ast.DefaultExpr{Expr: internal.PendingCommitTimestamp()}, nil
+	}
+	fv := f.Value(valV)
+	if f.Default && fv.IsZero() {
+		return &ast.DefaultExpr{Default: true}, nil
+	}
+	return toDefaultExpr(fv.Interface())
+}
+
+// toDefaultExpr converts val into a value of VALUES clause, which is either an expression or DEFAULT.
+func toDefaultExpr(val interface{}) (*ast.DefaultExpr, error) {
+	if _, ok := val.(DefaultValue); ok {
+		return &ast.DefaultExpr{Default: true}, nil
+	}
+	expr, err := internal.ToExpr(val)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.DefaultExpr{Expr: expr}, nil
+}
+
+// findStructField returns the field that corresponds to the column colName.
+func findStructField(fields []internal.StructField, colName string) (*internal.StructField, bool) {
+	for i := range fields {
+		if fields[i].Matches(colName) {
+			return &fields[i], true
+		}
 	}
-	return tag == colName
+	return nil, false
 }
@@ -0,0 +1,89 @@
+package memeduck
+
+import (
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/pkg/errors"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+// returning is a THEN RETURN clause of DML statements.
+type returning struct {
+	withAction  bool
+	actionAlias string
+	items       []interface{}
+}
+
+func (r *returning) toAST() (*internal.ThenReturn, error) {
+	if len(r.items) <= 0 {
+		return nil, errors.New("no items in THEN RETURN clause")
+	}
+	items := make([]ast.SelectItem, 0, len(r.items))
+	for _, item := range r.items {
+		astItem, err := toReturningItem(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, astItem)
+	}
+	return &internal.ThenReturn{
+		WithAction:  r.withAction,
+		ActionAlias: r.actionAlias,
+		Items:       items,
+	}, nil
+}
+
+// boundParams returns parameters bound to sub queries in the THEN RETURN clause.
+func (r *returning) boundParams() []binding {
+	if r == nil {
+		return nil
+	}
+	var params []binding
+	for _, item := range r.items {
+		switch q := item.(type) {
+		case *ScalarSubQueryStmt:
+			params = append(params, q.query.boundParams()...)
+		case *ArraySubQueryStmt:
+			params = append(params, q.query.boundParams()...)
+		}
+	}
+	return params
+}
+
+func toReturningItem(item interface{}) (ast.SelectItem, error) {
+	switch v := item.(type) {
+	case string:
+		if v == "*" {
+			return &ast.Star{}, nil
+		}
+		return &ast.ExprSelectItem{
+			Expr: &ast.Ident{Name: v},
+		}, nil
+	case SubQuery:
+		return v.ToAST()
+	default:
+		expr, err := internal.ToExpr(v)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ExprSelectItem{
+			Expr: expr,
+		}, nil
+	}
+}
+
+// toDML converts stmt into a DML statement with the THEN RETURN clause.
+func (r *returning) toDML(stmt ast.Node, mode internal.InsertMode) (*internal.DML, error) {
+	dml := &internal.DML{
+		Stmt:       stmt,
+		InsertMode: mode,
+	}
+	if r != nil {
+		thenReturn, err := r.toAST()
+		if err != nil {
+			return nil, err
+		}
+		dml.ThenReturn = thenReturn
+	}
+	return dml, nil
+}
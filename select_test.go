@@ -166,3 +166,35 @@ func TestSelectWithSubQuery(t *testing.T) {
 		`SELECT a, b, ARRAY(SELECT AS STRUCT c, d FROM fuga WHERE 3 = 4) AS fuga FROM hoge WHERE 1 = 2`,
 	)
 }
+
+func TestSelectWithTypedNull(t *testing.T) {
+	testSelect(t,
+		memeduck.Select("hoge", []string{"a", "b"}).Where(
+			memeduck.Eq(memeduck.Ident("a"), (*string)(nil)),
+			memeduck.In(memeduck.Ident("b"), memeduck.Unnest([]int64{})),
+		),
+		`SELECT a, b FROM hoge WHERE a = CAST(NULL AS STRING) AND b IN UNNEST(ARRAY<INT64>[])`,
+	)
+	testSelect(t,
+		memeduck.Select("hoge", []string{"a", "b"}).Where(
+			memeduck.Eq(memeduck.Ident("a"), (*string)(nil)),
+			memeduck.In(memeduck.Ident("b"), memeduck.Unnest([]int64{})),
+		).Untyped(),
+		`SELECT a, b FROM hoge WHERE a = NULL AND b IN UNNEST(ARRAY[])`,
+	)
+}
+
+func TestSelectWithEmptyConds(t *testing.T) {
+	testSelect(t,
+		memeduck.Select("hoge", []string{"a"}).Where(memeduck.And()),
+		`SELECT a FROM hoge`,
+	)
+	testSelect(t,
+		memeduck.Select("hoge", []string{"a"}).Where(memeduck.And(), memeduck.Eq(memeduck.Ident("a"), 1)),
+		`SELECT a FROM hoge WHERE a = 1`,
+	)
+	testSelect(t,
+		memeduck.Select("hoge", []string{"a"}).Where(memeduck.Or()),
+		`SELECT a FROM hoge WHERE FALSE`,
+	)
+}
@@ -0,0 +1,88 @@
+package memeduck
+
+import (
+	"reflect"
+	"sort"
+
+	"cloud.google.com/go/spanner"
+	"github.com/MakeNowJust/memefish/pkg/ast"
+	"github.com/pkg/errors"
+
+	"github.com/genkami/memeduck/internal"
+)
+
+func toSQL(stmt ast.Node, untyped bool) (string, error) {
+	if untyped {
+		if err := internal.Untype(stmt); err != nil {
+			return "", err
+		}
+	}
+	return stmt.SQL(), nil
+}
+
+func toStatement(stmt ast.Node, untyped bool, bound []binding) (spanner.Statement, error) {
+	if untyped {
+		if err := internal.Untype(stmt); err != nil {
+			return spanner.Statement{}, err
+		}
+	}
+	explicit, err := bindingsToMap(bound)
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	used := internal.ParamNames(stmt)
+	for _, name := range sortedKeys(used) {
+		if _, ok := explicit[name]; !ok {
+			return spanner.Statement{}, errors.Errorf("query parameter @%s is not bound", name)
+		}
+	}
+	for _, name := range sortedKeys(explicit) {
+		if !used[name] {
+			return spanner.Statement{}, errors.Errorf("query parameter @%s is bound but not used", name)
+		}
+	}
+	params, err := internal.Parameterize(stmt)
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	for name, value := range explicit {
+		params[name] = value
+	}
+	return spanner.Statement{
+		SQL:    stmt.SQL(),
+		Params: params,
+	}, nil
+}
+
+// binding is a value bound to a query parameter.
+type binding struct {
+	name  string
+	value interface{}
+}
+
+func appendBindings(bs []binding, params map[string]interface{}) []binding {
+	for _, name := range sortedKeys(params) {
+		bs = append(bs, binding{name: name, value: params[name]})
+	}
+	return bs
+}
+
+func bindingsToMap(bs []binding) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	for _, b := range bs {
+		if v, ok := params[b.name]; ok && !reflect.DeepEqual(v, b.value) {
+			return nil, errors.Errorf("conflicting values are bound to query parameter @%s: %#v and %#v", b.name, v, b.value)
+		}
+		params[b.name] = b.value
+	}
+	return params, nil
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
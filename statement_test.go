@@ -0,0 +1,240 @@
+package memeduck_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/genkami/memeduck"
+)
+
+type statementBuilder interface {
+	Statement() (spanner.Statement, error)
+}
+
+func testStatement(t *testing.T, stmt statementBuilder, expectedSQL string, expectedParams map[string]interface{}) {
+	actual, err := stmt.Statement()
+	assert.Nil(t, err, expectedSQL)
+	assert.Equal(t, expectedSQL, actual.SQL)
+	assert.Equal(t, expectedParams, actual.Params)
+}
+
+func TestSelectStatement(t *testing.T) {
+	testStatement(t,
+		memeduck.Select("hoge", []string{"a", "b"}).Where(
+			memeduck.Eq(memeduck.Ident("a"), "foo"),
+			memeduck.Gt(memeduck.Ident("b"), 123),
+			memeduck.Ne(memeduck.Ident("c"), "foo"),
+			memeduck.Eq(memeduck.Ident("d"), true),
+			memeduck.IsNull(memeduck.Ident("e")),
+		).Limit(10),
+		`SELECT a, b FROM hoge WHERE a = @p1 AND b > @p2 AND c != @p1 AND d = TRUE AND e IS NULL LIMIT 10`,
+		map[string]interface{}{
+			"p1": "foo",
+			"p2": int64(123),
+		},
+	)
+	testStatement(t,
+		memeduck.Select("hoge", []string{"a"}),
+		`SELECT a FROM hoge`,
+		map[string]interface{}{},
+	)
+}
+
+func TestSelectStatementWithSubQuery(t *testing.T) {
+	testStatement(t,
+		memeduck.Select("hoge", []string{"a"}).
+			SubQuery(
+				memeduck.ArraySubQuery(memeduck.Select("fuga", []string{"b"}).Where(memeduck.Eq(memeduck.Ident("c"), "x"))).As("fuga"),
+			).
+			Where(memeduck.Eq(memeduck.Ident("d"), "y")),
+		`SELECT a, ARRAY(SELECT b FROM fuga WHERE c = @p1) AS fuga FROM hoge WHERE d = @p2`,
+		map[string]interface{}{
+			"p1": "x",
+			"p2": "y",
+		},
+	)
+}
+
+func TestSelectStatementWithExplicitParams(t *testing.T) {
+	testStatement(t,
+		memeduck.Select("hoge", []string{"a"}).Where(
+			memeduck.Eq(memeduck.Ident("a"), memeduck.Param("p1")),
+			memeduck.Eq(memeduck.Ident("b"), "foo"),
+		).Params(map[string]interface{}{"p1": 123}),
+		`SELECT a FROM hoge WHERE a = @p1 AND b = @p2`,
+		map[string]interface{}{
+			"p1": 123,
+			"p2": "foo",
+		},
+	)
+}
+
+func TestSelectStatementWithArrays(t *testing.T) {
+	var s = "foo"
+	testStatement(t,
+		memeduck.Select("hoge", []string{"a"}).Where(
+			memeduck.In(memeduck.Ident("a"), memeduck.Unnest([]int64{1, 2})),
+			memeduck.In(memeduck.Ident("b"), memeduck.Unnest([]*string{&s, nil})),
+			memeduck.In(memeduck.Ident("c"), memeduck.Unnest([]string{})),
+			memeduck.In(memeduck.Ident("d"), memeduck.Unnest([]interface{}{})),
+			memeduck.In(memeduck.Ident("e"), memeduck.Unnest([]interface{}{memeduck.Ident("x"), 1})),
+		),
+		`SELECT a FROM hoge WHERE a IN UNNEST(@p1) AND b IN UNNEST(@p2) AND c IN UNNEST(@p3) AND d IN UNNEST(ARRAY[]) AND e IN UNNEST(ARRAY[x, @p4])`,
+		map[string]interface{}{
+			"p1": []int64{1, 2},
+			"p2": []spanner.NullString{{StringVal: "foo", Valid: true}, {}},
+			"p3": []string{},
+			"p4": int64(1),
+		},
+	)
+}
+
+func TestInsertStatement(t *testing.T) {
+	var ts = time.Date(2021, 5, 22, 12, 34, 56, 0, time.UTC)
+	var date = civil.Date{Year: 2021, Month: 5, Day: 22}
+	testStatement(t,
+		memeduck.Insert("hoge", []string{"a", "b", "c", "d", "e", "f", "g"}).Values([][]interface{}{
+			{"foo", []byte{1}, 1.5, ts, date, big.NewRat(1, 4), nil},
+			{"bar", []byte{1}, float32(2.5), ts, date, big.NewRat(1, 4), (*string)(nil)},
+		}),
+		`INSERT INTO hoge (a, b, c, d, e, f, g) VALUES `+
+			`(@p1, @p2, @p3, @p4, @p5, @p6, NULL), `+
+			`(@p7, @p2, CAST(@p8 AS FLOAT32), @p4, @p5, @p6, CAST(NULL AS STRING))`,
+		map[string]interface{}{
+			"p1": "foo",
+			"p2": []byte{1},
+			"p3": 1.5,
+			"p4": ts,
+			"p5": date,
+			"p6": *big.NewRat(1, 4),
+			"p7": "bar",
+			"p8": 2.5,
+		},
+	)
+}
+
+func TestUpdateStatement(t *testing.T) {
+	testStatement(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), "foo").
+			Set(memeduck.Ident("b"), (*int64)(nil)).
+			Where(memeduck.Eq(memeduck.Ident("c"), 1)).
+			Untyped(),
+		`UPDATE hoge SET a = @p1, b = NULL WHERE c = @p2`,
+		map[string]interface{}{
+			"p1": "foo",
+			"p2": int64(1),
+		},
+	)
+}
+
+func TestDeleteStatement(t *testing.T) {
+	testStatement(t,
+		memeduck.Delete("hoge").Where(
+			memeduck.Between(memeduck.Ident("a"), 1, 10),
+		),
+		`DELETE FROM hoge WHERE a BETWEEN @p1 AND @p2`,
+		map[string]interface{}{
+			"p1": int64(1),
+			"p2": int64(10),
+		},
+	)
+}
+
+func TestStatementWithInvalidStatement(t *testing.T) {
+	_, err := memeduck.Delete("hoge").Statement()
+	assert.Error(t, err)
+}
+
+func TestStatementWithBoundParams(t *testing.T) {
+	testStatement(t,
+		memeduck.Select("hoge", []string{"a"}).
+			SubQuery(
+				memeduck.ScalarSubQuery(
+					memeduck.Select("fuga", []string{"b"}).
+						Where(memeduck.Eq(memeduck.Ident("c"), memeduck.Param("c"))).
+						Params(map[string]interface{}{"c": "x"}),
+				).As("b"),
+			).
+			Where(memeduck.Eq(memeduck.Ident("age"), memeduck.Param("age"))).
+			Params(map[string]interface{}{"age": 30}).
+			Params(map[string]interface{}{"age": 30}),
+		`SELECT a, (SELECT b FROM fuga WHERE c = @c) AS b FROM hoge WHERE age = @age`,
+		map[string]interface{}{
+			"age": 30,
+			"c":   "x",
+		},
+	)
+	testStatement(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), memeduck.Param("a")).
+			Where(memeduck.Eq(memeduck.Ident("b"), 1)).
+			Params(map[string]interface{}{"a": "foo"}),
+		`UPDATE hoge SET a = @a WHERE b = @p1`,
+		map[string]interface{}{
+			"a":  "foo",
+			"p1": int64(1),
+		},
+	)
+	testStatement(t,
+		memeduck.Delete("hoge").
+			Where(memeduck.Eq(memeduck.Ident("a"), memeduck.Param("a"))).
+			Params(map[string]interface{}{"a": "foo"}),
+		`DELETE FROM hoge WHERE a = @a`,
+		map[string]interface{}{
+			"a": "foo",
+		},
+	)
+	testStatement(t,
+		memeduck.Insert("hoge", []string{"a"}).
+			Values([][]interface{}{{memeduck.Param("a")}}).
+			Params(map[string]interface{}{"a": "foo"}),
+		`INSERT INTO hoge (a) VALUES (@a)`,
+		map[string]interface{}{
+			"a": "foo",
+		},
+	)
+}
+
+func TestStatementWithUnboundParam(t *testing.T) {
+	_, err := memeduck.Select("hoge", []string{"a"}).
+		Where(
+			memeduck.Eq(memeduck.Ident("a"), memeduck.Param("a")),
+			memeduck.Eq(memeduck.Ident("b"), memeduck.Param("b")),
+		).
+		Params(map[string]interface{}{"a": 1}).
+		Statement()
+	assert.EqualError(t, err, "query parameter @b is not bound")
+}
+
+func TestStatementWithConflictingParams(t *testing.T) {
+	_, err := memeduck.Delete("hoge").
+		Where(memeduck.Eq(memeduck.Ident("a"), memeduck.Param("a"))).
+		Params(map[string]interface{}{"a": 1}).
+		Params(map[string]interface{}{"a": 2}).
+		Statement()
+	assert.Error(t, err, "conflicting bindings")
+}
+
+func TestStatementWithUnusedParam(t *testing.T) {
+	_, err := memeduck.Update("hoge").
+		Set(memeduck.Ident("a"), 1).
+		Where(memeduck.Bool(true)).
+		Params(map[string]interface{}{"a": 1}).
+		Statement()
+	assert.EqualError(t, err, "query parameter @a is bound but not used")
+}
+
+func TestSQLIgnoresBoundParams(t *testing.T) {
+	testSelect(t,
+		memeduck.Select("hoge", []string{"a"}).
+			Where(memeduck.Eq(memeduck.Ident("a"), memeduck.Param("a"))).
+			Params(map[string]interface{}{"a": 1}),
+		`SELECT a FROM hoge WHERE a = @a`,
+	)
+}
@@ -0,0 +1,180 @@
+package memeduck
+
+import (
+	"reflect"
+
+	"cloud.google.com/go/spanner"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+)
+
+// RowIterator is a source of rows that are read lazily.
+// Each row is converted in the same way as elements of the slice given to InsertStmt.Values.
+type RowIterator interface {
+	// Next returns the next row, or iterator.Done if there are no more rows.
+	Next() (interface{}, error)
+}
+
+// RowIteratorFunc is an adapter to use an ordinary function as RowIterator.
+type RowIteratorFunc func() (interface{}, error)
+
+func (f RowIteratorFunc) Next() (interface{}, error) {
+	return f()
+}
+
+// ChanRows creates a RowIterator that receives rows from ch until it is closed.
+// ch must be a channel that can receive values.
+func ChanRows(ch interface{}) RowIterator {
+	chV := reflect.ValueOf(ch)
+	if chV.Kind() != reflect.Chan || chV.Type().ChanDir()&reflect.RecvDir == 0 {
+		return RowIteratorFunc(func() (interface{}, error) {
+			return nil, errors.Errorf("can't receive rows from %T", ch)
+		})
+	}
+	return RowIteratorFunc(func() (interface{}, error) {
+		row, ok := chV.Recv()
+		if !ok {
+			return nil, iterator.Done
+		}
+		return row.Interface(), nil
+	})
+}
+
+// StatementIterator yields INSERT statements made from rows that are read lazily.
+type StatementIterator struct {
+	stmt   *InsertStmt
+	rows   RowIterator
+	limits BatchLimits
+	b      *batcher
+	buf    []interface{}
+	done   bool
+	err    error
+}
+
+// BatchRows returns a StatementIterator that reads rows from rows and splits them into statements
+// in the same way as Batch, without reading all rows into memory at once.
+// The INSERT statement must not have values; Next returns an error otherwise.
+//
+// At least one of limits must be specified, since all rows would be read into a single statement otherwise.
+// If column names are not specified, they are inferred from the type of the first row.
+func (s *InsertStmt) BatchRows(rows RowIterator, limits BatchLimits) *StatementIterator {
+	return &StatementIterator{
+		stmt:   s,
+		rows:   rows,
+		limits: limits,
+	}
+}
+
+// Next returns the next statement, or iterator.Done if all rows are consumed.
+// Once it returns an error, it returns the same error on subsequent calls.
+func (it *StatementIterator) Next() (spanner.Statement, error) {
+	if it.err != nil {
+		return spanner.Statement{}, it.err
+	}
+	stmt, err := it.next()
+	if err != nil {
+		it.err = err
+		return spanner.Statement{}, err
+	}
+	return stmt, nil
+}
+
+func (it *StatementIterator) next() (spanner.Statement, error) {
+	if it.b == nil {
+		if err := it.init(); err != nil {
+			return spanner.Statement{}, err
+		}
+	}
+	if err := it.fill(); err != nil {
+		return spanner.Statement{}, err
+	}
+	if len(it.buf) <= 0 {
+		if err := it.b.checkUnused(); err != nil {
+			return spanner.Statement{}, err
+		}
+		return spanner.Statement{}, iterator.Done
+	}
+	stmt, n, err := it.b.split(it.buf)
+	if err != nil {
+		return spanner.Statement{}, err
+	}
+	it.buf = append(it.buf[:0], it.buf[n:]...)
+	return stmt, nil
+}
+
+// init creates a batcher, inferring column names from the first row if necessary.
+func (it *StatementIterator) init() error {
+	if it.stmt.query != nil {
+		return errors.New("INSERT ... SELECT can't be split into batches")
+	}
+	if it.stmt.values != nil {
+		return errors.New("VALUES can't be given to BatchRows")
+	}
+	if it.limits == (BatchLimits{}) {
+		return errors.New("no batch limits specified")
+	}
+	var t = *it.stmt
+	if t.cols == nil {
+		if err := it.read(1); err != nil {
+			return err
+		}
+		if len(it.buf) <= 0 {
+			// There's nothing to insert, nor to infer column names from.
+			if err := checkUnusedParams(&t, nil); err != nil {
+				return err
+			}
+			return iterator.Done
+		}
+		cols, err := inferRowColumns(reflect.TypeOf(it.buf[0]))
+		if err != nil {
+			return err
+		}
+		t.cols = cols
+	}
+	b, err := newBatcher(&t, it.limits)
+	if err != nil {
+		return err
+	}
+	it.b = b
+	return nil
+}
+
+// fill reads rows into the buffer until it has enough rows to make the next statement.
+func (it *StatementIterator) fill() error {
+	if it.b.maxRows > 0 {
+		return it.read(it.b.maxRows)
+	}
+	// The number of rows that fit in MaxBytes is unknown, so read twice as many rows as buffered until they don't fit.
+	for !it.done {
+		n := 2 * len(it.buf)
+		if n <= 0 {
+			n = 1
+		}
+		if err := it.read(n); err != nil {
+			return err
+		}
+		stmt, err := it.b.statement(it.buf)
+		if err != nil {
+			return err
+		}
+		if !it.b.fits(stmt) {
+			break
+		}
+	}
+	return nil
+}
+
+// read reads rows into the buffer until it has n rows or no more rows are available.
+func (it *StatementIterator) read(n int) error {
+	for !it.done && len(it.buf) < n {
+		row, err := it.rows.Next()
+		if err == iterator.Done {
+			it.done = true
+			break
+		} else if err != nil {
+			return err
+		}
+		it.buf = append(it.buf, row)
+	}
+	return nil
+}
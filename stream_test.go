@@ -0,0 +1,146 @@
+package memeduck_test
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/iterator"
+
+	"github.com/genkami/memeduck"
+)
+
+func sliceRows(rows ...interface{}) memeduck.RowIterator {
+	return memeduck.RowIteratorFunc(func() (interface{}, error) {
+		if len(rows) <= 0 {
+			return nil, iterator.Done
+		}
+		row := rows[0]
+		rows = rows[1:]
+		return row, nil
+	})
+}
+
+func collectStatements(t *testing.T, it *memeduck.StatementIterator) []string {
+	var sqls []string
+	for {
+		stmt, err := it.Next()
+		if err == iterator.Done {
+			return sqls
+		}
+		if !assert.Nil(t, err) {
+			return sqls
+		}
+		sqls = append(sqls, stmt.SQL)
+	}
+}
+
+func TestInsertBatchRows(t *testing.T) {
+	stmt := memeduck.Insert("hoge", []string{"a", "b"})
+	rows := func() memeduck.RowIterator {
+		return sliceRows([]int{1, 2}, []int{3, 4}, []int{5, 6}, []int{7, 8}, []int{9, 10})
+	}
+	assert.Equal(t, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4), (@p5, @p6), (@p7, @p8), (@p9, @p10)`,
+	}, collectStatements(t, stmt.BatchRows(rows(), memeduck.BatchLimits{MaxRows: 10})))
+	assert.Equal(t, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4), (@p5, @p6)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+	}, collectStatements(t, stmt.BatchRows(rows(), memeduck.BatchLimits{MaxMutations: 6})))
+	assert.Equal(t, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2)`,
+	}, collectStatements(t, stmt.BatchRows(rows(), memeduck.BatchLimits{MaxBytes: 70})))
+	assert.Equal(t, []string{
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4), (@p5, @p6)`,
+		`INSERT INTO hoge (a, b) VALUES (@p1, @p2), (@p3, @p4)`,
+	}, collectStatements(t, stmt.BatchRows(rows(), memeduck.BatchLimits{MaxBytes: 85})))
+	assert.Empty(t, collectStatements(t, stmt.BatchRows(sliceRows(), memeduck.BatchLimits{MaxRows: 2})))
+	assert.Empty(t, collectStatements(t, memeduck.Insert("hoge", nil).BatchRows(sliceRows(), memeduck.BatchLimits{MaxRows: 2})))
+}
+
+func TestInsertBatchRowsReadsLazily(t *testing.T) {
+	read := 0
+	rows := memeduck.RowIteratorFunc(func() (interface{}, error) {
+		read++
+		return []int{read}, nil
+	})
+	it := memeduck.Insert("hoge", []string{"a"}).BatchRows(rows, memeduck.BatchLimits{MaxRows: 3})
+	stmt, err := it.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, spanner.Statement{
+		SQL:    `INSERT INTO hoge (a) VALUES (@p1), (@p2), (@p3)`,
+		Params: map[string]interface{}{"p1": int64(1), "p2": int64(2), "p3": int64(3)},
+	}, stmt)
+	assert.Equal(t, 3, read)
+	_, err = it.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, 6, read)
+}
+
+func TestInsertBatchRowsWithoutLimits(t *testing.T) {
+	read := 0
+	rows := memeduck.RowIteratorFunc(func() (interface{}, error) {
+		read++
+		return []int{read}, nil
+	})
+	for _, stmt := range []*memeduck.InsertStmt{
+		memeduck.Insert("hoge", []string{"a"}),
+		memeduck.Insert("hoge", nil),
+	} {
+		it := stmt.BatchRows(rows, memeduck.BatchLimits{})
+		_, err := it.Next()
+		assert.Error(t, err)
+		assert.NotEqual(t, iterator.Done, err)
+		assert.Equal(t, 0, read)
+	}
+}
+
+func TestInsertBatchRowsWithChan(t *testing.T) {
+	ch := make(chan testInsertGoStructWithTags)
+	go func() {
+		defer close(ch)
+		for _, a := range []string{"x", "y", "z"} {
+			ch <- testInsertGoStructWithTags{A: a}
+		}
+	}()
+	it := memeduck.Insert("hoge", nil).BatchRows(memeduck.ChanRows(ch), memeduck.BatchLimits{MaxRows: 2})
+	assert.Equal(t, []string{
+		`INSERT INTO hoge (ColumnA, ColumnB, C) VALUES (@p1, @p2, @p2), (@p3, @p2, @p2)`,
+		`INSERT INTO hoge (ColumnA, ColumnB, C) VALUES (@p1, @p2, @p2)`,
+	}, collectStatements(t, it))
+}
+
+func TestInsertBatchRowsWithErrors(t *testing.T) {
+	errRows := errors.New("broken rows")
+	rows := memeduck.RowIteratorFunc(func() (interface{}, error) {
+		return nil, errRows
+	})
+	it := memeduck.Insert("hoge", []string{"a"}).BatchRows(rows, memeduck.BatchLimits{MaxRows: 10})
+	_, err := it.Next()
+	assert.Equal(t, errRows, err)
+	_, err = it.Next()
+	assert.Equal(t, errRows, err)
+
+	for _, it := range []*memeduck.StatementIterator{
+		memeduck.Insert("hoge", []string{"a"}).BatchRows(memeduck.ChanRows(1), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", []string{"a"}).BatchRows(memeduck.ChanRows(make(chan<- int)), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", nil).BatchRows(sliceRows([]int{1}), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", []string{"a"}).BatchRows(sliceRows([]int{1}), memeduck.BatchLimits{MaxBytes: 10}),
+		memeduck.Insert("hoge", []string{"a"}).BatchRows(sliceRows([]int{1}), memeduck.BatchLimits{MaxRows: -1}),
+		memeduck.Insert("hoge", []string{"a"}).Params(map[string]interface{}{"x": 1}).BatchRows(sliceRows([]int{1}), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", []string{"a"}).Params(map[string]interface{}{"x": 1}).BatchRows(sliceRows(), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", nil).Params(map[string]interface{}{"x": 1}).BatchRows(sliceRows(), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", []string{"a"}).Values([][]int{{1}}).BatchRows(sliceRows([]int{2}), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", []string{"a"}).Values([][]int{}).BatchRows(sliceRows(), memeduck.BatchLimits{MaxRows: 10}),
+		memeduck.Insert("hoge", []string{"a"}).Select(memeduck.Select("fuga", []string{"a"})).BatchRows(sliceRows([]int{1}), memeduck.BatchLimits{MaxRows: 10}),
+	} {
+		var err error
+		for err == nil {
+			_, err = it.Next()
+		}
+		assert.NotEqual(t, iterator.Done, err)
+	}
+}
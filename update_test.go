@@ -3,6 +3,7 @@ package memeduck_test
 import (
 	"testing"
 
+	"cloud.google.com/go/spanner"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/genkami/memeduck"
@@ -89,5 +90,87 @@ func TestUpdateWithNoWhere(t *testing.T) {
 	_, err := memeduck.Update("hoge").
 		Set(memeduck.Ident("a"), 1).
 		SQL()
-	assert.Error(t, err, "UPDATE without WHERE clause")
+	assert.ErrorIs(t, err, memeduck.ErrNoWhereClause)
+}
+
+func TestUpdateWithAllRows(t *testing.T) {
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), 1).
+			AllRows(),
+		`UPDATE hoge SET a = 1 WHERE TRUE`,
+	)
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), 1).
+			Where(memeduck.Eq(memeduck.Ident("b"), 2)).
+			AllRows(),
+		`UPDATE hoge SET a = 1 WHERE b = 2`,
+	)
+}
+
+func TestUpdateWithUntyped(t *testing.T) {
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), (*int64)(nil)).
+			Where(memeduck.Bool(true)),
+		`UPDATE hoge SET a = CAST(NULL AS INT64) WHERE TRUE`,
+	)
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), (*int64)(nil)).
+			Where(memeduck.Bool(true)).
+			Untyped(),
+		`UPDATE hoge SET a = NULL WHERE TRUE`,
+	)
+}
+
+func TestUpdateWithEmptyConds(t *testing.T) {
+	_, err := memeduck.Update("hoge").
+		Set(memeduck.Ident("a"), 1).
+		Where(memeduck.And()).
+		SQL()
+	assert.ErrorIs(t, err, memeduck.ErrNoWhereClause)
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), 1).
+			Where(memeduck.And(), memeduck.Eq(memeduck.Ident("b"), 2)),
+		`UPDATE hoge SET a = 1 WHERE b = 2`,
+	)
+}
+
+func TestUpdateWithThenReturn(t *testing.T) {
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), 1).
+			Where(memeduck.Eq(memeduck.Ident("b"), 2)).
+			ThenReturn("a", "b"),
+		`UPDATE hoge SET a = 1 WHERE b = 2 THEN RETURN a, b`,
+	)
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), 1).
+			Where(memeduck.Eq(memeduck.Ident("b"), 2)).
+			ThenReturnWithAction("", "*"),
+		`UPDATE hoge SET a = 1 WHERE b = 2 THEN RETURN WITH ACTION *`,
+	)
+}
+
+func TestUpdateWithDefault(t *testing.T) {
+	testUpdate(t,
+		memeduck.Update("hoge").
+			Set(memeduck.Ident("a"), memeduck.Default).
+			Set(memeduck.Ident("b", "c"), 1).
+			Set(memeduck.Ident("d"), memeduck.Default).
+			Where(memeduck.Eq(memeduck.Ident("e"), 2)),
+		`UPDATE hoge SET a = DEFAULT, b.c = 1, d = DEFAULT WHERE e = 2`,
+	)
+	stmt, err := memeduck.Update("hoge").
+		Set(memeduck.Ident("a"), memeduck.Default).
+		Set(memeduck.Ident("b"), spanner.CommitTimestamp).
+		Where(memeduck.Eq(memeduck.Ident("c"), 1)).
+		Statement()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE hoge SET a = DEFAULT, b = PENDING_COMMIT_TIMESTAMP() WHERE c = @p1`, stmt.SQL)
+	assert.Equal(t, map[string]interface{}{"p1": int64(1)}, stmt.Params)
 }
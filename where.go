@@ -1,6 +1,9 @@
 package memeduck
 
 import (
+	"reflect"
+	"sort"
+
 	"github.com/MakeNowJust/memefish/pkg/ast"
 	"github.com/pkg/errors"
 
@@ -115,6 +118,28 @@ func NotLike(lhs, rhs interface{}) *OpCond {
 	return Op(lhs, NOT_LIKE, rhs)
 }
 
+// OpIfNotNil creates a new binary operator expression only if rhs is not NULL.
+// Otherwise it returns nil, which is ignored by And, Or and Where.
+func OpIfNotNil(lhs interface{}, op BinaryOp, rhs interface{}) WhereCond {
+	if internal.IsNull(rhs) {
+		return nil
+	}
+	return Op(lhs, op, rhs)
+}
+
+// EqIfNotNil(x, y) is a shorthand for OpIfNotNil(x, EQ, y)
+func EqIfNotNil(lhs, rhs interface{}) WhereCond {
+	return OpIfNotNil(lhs, EQ, rhs)
+}
+
+// When returns cond if ok is true. Otherwise it returns nil, which is ignored by And, Or and Where.
+func When(ok bool, cond WhereCond) WhereCond {
+	if !ok {
+		return nil
+	}
+	return cond
+}
+
 // NullCond represents IS NULL or IS NOT NULL predicate.
 type NullCond struct {
 	not bool
@@ -247,6 +272,133 @@ func (c *BetweenCond) ToASTWhere() (*ast.Where, error) {
 	}, nil
 }
 
+// MapCond is a set of equality conditions built from a map.
+type MapCond struct {
+	m map[string]interface{}
+}
+
+// EqMap creates conditions that each column in m equals to the corresponding value.
+// Conditions are concatenated with AND operator in the order of column names.
+// NULL values are converted into `x IS NULL` and slices (except for []byte) are converted into `x IN UNNEST(...)`.
+func EqMap(m map[string]interface{}) *MapCond {
+	return &MapCond{m: m}
+}
+
+func (c *MapCond) ToASTWhere() (*ast.Where, error) {
+	cond, err := c.toLogicalOpCond()
+	if err != nil {
+		return nil, err
+	}
+	return cond.ToASTWhere()
+}
+
+func (c *MapCond) toLogicalOpCond() (*LogicalOpCond, error) {
+	cols := make([]string, 0, len(c.m))
+	for col := range c.m {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	conds := make([]WhereCond, 0, len(cols))
+	for _, col := range cols {
+		conds = append(conds, eqCond(Ident(col), c.m[col]))
+	}
+	return And(conds...), nil
+}
+
+// eqCond creates an equality condition that also works with NULLs and slices.
+func eqCond(lhs, rhs interface{}) WhereCond {
+	if internal.IsNull(rhs) {
+		return IsNull(lhs)
+	}
+	rhsT := reflect.TypeOf(rhs)
+	if rhsT.Kind() == reflect.Slice && rhsT.Elem().Kind() != reflect.Uint8 {
+		return In(lhs, Unnest(rhs))
+	}
+	return Eq(lhs, rhs)
+}
+
+// isArrayType reports whether values of t are converted into ARRAYs.
+func isArrayType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// StructCond is a set of equality conditions built from a struct, which is also known as query-by-example.
+type StructCond struct {
+	val         interface{}
+	includeZero []string
+}
+
+// EqStruct creates conditions that each column equals to the corresponding field of v.
+// v must be a struct or a pointer to it, and its fields are mapped to columns in the same way as Insert.
+// Fields that have zero values are ignored unless they are specified by IncludeZero.
+// Conditions are concatenated with AND operator in the order of fields, and NULL values are converted into `x IS NULL`.
+// Slice fields (except for []byte) must have zero values and must not be specified by IncludeZero,
+// since ARRAYs can't be compared with = in Spanner.
+func EqStruct(v interface{}) *StructCond {
+	return &StructCond{val: v}
+}
+
+// IncludeZero makes conditions on the given columns even if the corresponding fields have zero values.
+func (c *StructCond) IncludeZero(cols ...string) *StructCond {
+	var t = *c
+	t.includeZero = append(append([]string{}, c.includeZero...), cols...)
+	return &t
+}
+
+func (c *StructCond) ToASTWhere() (*ast.Where, error) {
+	cond, err := c.toLogicalOpCond()
+	if err != nil {
+		return nil, err
+	}
+	return cond.ToASTWhere()
+}
+
+func (c *StructCond) toLogicalOpCond() (*LogicalOpCond, error) {
+	valV := reflect.ValueOf(c.val)
+	if valV.Kind() == reflect.Ptr && !valV.IsNil() {
+		valV = valV.Elem()
+	}
+	if valV.Kind() != reflect.Struct {
+		return nil, errors.Errorf("%T is not a struct", c.val)
+	}
+	valT := valV.Type()
+	fields, err := internal.StructFields(valT)
+	if err != nil {
+		return nil, err
+	}
+	included := make([]bool, len(c.includeZero))
+	var conds []WhereCond
+	for _, f := range fields {
+		include := false
+		for j, col := range c.includeZero {
+			if f.Matches(col) {
+				included[j] = true
+				include = true
+			}
+		}
+		fv := f.Value(valV)
+		if fv.IsZero() && !include {
+			continue
+		}
+		if internal.IsNull(fv.Interface()) {
+			conds = append(conds, IsNull(Ident(f.Name)))
+		} else if isArrayType(f.Type) {
+			return nil, errors.Errorf("can't compare ARRAY column %s of type %s", f.Name, valT.String())
+		} else {
+			conds = append(conds, Eq(Ident(f.Name), fv.Interface()))
+		}
+	}
+	for j, col := range c.includeZero {
+		if !included[j] {
+			return nil, errors.Errorf("type %s does not have column %s", valT.String(), col)
+		}
+	}
+	return And(conds...), nil
+}
+
 // IdentExpr is an identifier.
 type IdentExpr struct {
 	names []string
@@ -293,12 +445,18 @@ type LogicalOpCond struct {
 
 type logicalOp ast.BinaryOp
 
+// compositeCond is a condition that consists of other conditions concatenated with AND or OR.
+type compositeCond interface {
+	toLogicalOpCond() (*LogicalOpCond, error)
+}
+
 const (
 	logicalOpAnd logicalOp = logicalOp(ast.OpAnd)
 	logicalOpOr  logicalOp = logicalOp(ast.OpOr)
 )
 
 // And concatenates more than one WhereConds with AND operator.
+// Nil conditions are ignored, and AND whose operands are all nil doesn't filter anything.
 func And(conds ...WhereCond) *LogicalOpCond {
 	return &LogicalOpCond{
 		op:    logicalOpAnd,
@@ -307,6 +465,7 @@ func And(conds ...WhereCond) *LogicalOpCond {
 }
 
 // Or concatenates more than one WhereConds with OR operator.
+// Nil conditions are ignored, and OR whose operands are all nil doesn't filter anything.
 func Or(conds ...WhereCond) *LogicalOpCond {
 	return &LogicalOpCond{
 		op:    logicalOpOr,
@@ -314,27 +473,114 @@ func Or(conds ...WhereCond) *LogicalOpCond {
 	}
 }
 
+func (c *LogicalOpCond) toLogicalOpCond() (*LogicalOpCond, error) {
+	return c, nil
+}
+
+// ToASTWhere converts the operator into WHERE clause.
+// Empty AND is TRUE and empty OR is FALSE. Nil operands and empty operators of the same kind in its operands are omitted.
+// Operators whose operands are all nil are omitted as well, or TRUE if there's nothing else.
 func (c *LogicalOpCond) ToASTWhere() (*ast.Where, error) {
-	if len(c.conds) <= 0 {
-		return nil, errors.New("no conditions")
+	conds := make([]WhereCond, 0, len(c.conds))
+	for _, cond := range c.conds {
+		if !isIdentityCond(cond, c.op) {
+			conds = append(conds, cond)
+		}
 	}
-	where, err := c.conds[0].ToASTWhere()
+	if len(conds) <= 0 {
+		return &ast.Where{
+			Expr: internal.BoolLit(c.op == logicalOpAnd || isAbsentCond(c)),
+		}, nil
+	}
+	acc, err := c.operand(conds[0])
 	if err != nil {
 		return nil, err
 	}
-	acc := where
-	for _, cond := range c.conds[1:] {
-		where, err = cond.ToASTWhere()
+	for _, cond := range conds[1:] {
+		expr, err := c.operand(cond)
 		if err != nil {
 			return nil, err
 		}
-		acc = &ast.Where{
-			Expr: &ast.BinaryExpr{
-				Op:    ast.BinaryOp(c.op),
-				Left:  acc.Expr,
-				Right: where.Expr,
-			},
+		acc = &ast.BinaryExpr{
+			Op:    ast.BinaryOp(c.op),
+			Left:  acc,
+			Right: expr,
 		}
 	}
-	return acc, nil
+	return &ast.Where{
+		Expr: acc,
+	}, nil
+}
+
+// operand converts cond into an operand of the operator.
+// OR in AND is parenthesized since AND has higher precedence than OR.
+func (c *LogicalOpCond) operand(cond WhereCond) (ast.Expr, error) {
+	where, err := cond.ToASTWhere()
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := where.Expr.(*ast.BinaryExpr); ok && c.op == logicalOpAnd && e.Op == ast.OpOr {
+		return &ast.ParenExpr{Expr: e}, nil
+	}
+	return where.Expr, nil
+}
+
+// isIdentityCond reports whether cond is an identity element of op, that is,
+// an empty AND (which is always TRUE) when op is AND, or an empty OR (which is always FALSE) when op is OR.
+// Absent conditions are treated as identity elements of any operator.
+func isIdentityCond(cond WhereCond, op logicalOp) bool {
+	if isAbsentCond(cond) {
+		return true
+	}
+	cc, ok := cond.(compositeCond)
+	if !ok {
+		return false
+	}
+	c, err := cc.toLogicalOpCond()
+	if err != nil || c.op != op {
+		// The error is reported later by ToASTWhere.
+		return false
+	}
+	for _, sub := range c.conds {
+		if !isIdentityCond(sub, op) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAbsentCond reports whether cond is nil, or AND/OR that has operands but all of them are absent.
+// Unlike explicitly empty AND/OR, absent conditions don't affect the result of the enclosing operator.
+func isAbsentCond(cond WhereCond) bool {
+	if isNilCond(cond) {
+		return true
+	}
+	cc, ok := cond.(compositeCond)
+	if !ok {
+		return false
+	}
+	c, err := cc.toLogicalOpCond()
+	if err != nil || len(c.conds) <= 0 {
+		return false
+	}
+	for _, sub := range c.conds {
+		if !isAbsentCond(sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNilCond reports whether cond is nil or a typed nil pointer like (*OpCond)(nil).
+func isNilCond(cond WhereCond) bool {
+	if cond == nil {
+		return true
+	}
+	v := reflect.ValueOf(cond)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// isEmptyConds reports whether conds don't filter anything at all.
+func isEmptyConds(conds []WhereCond) bool {
+	return isIdentityCond(And(conds...), logicalOpAnd)
 }
@@ -3,6 +3,7 @@ package memeduck_test
 import (
 	"testing"
 
+	"cloud.google.com/go/spanner"
 	"github.com/MakeNowJust/memefish/pkg/ast"
 	"github.com/genkami/memeduck"
 	"github.com/stretchr/testify/assert"
@@ -78,6 +79,18 @@ func TestIn(t *testing.T) {
 	testWhere(t, memeduck.In(memeduck.Ident("hoge"), memeduck.Unnest([]string{"foo", "bar"})), `hoge IN UNNEST(ARRAY["foo", "bar"])`)
 }
 
+type testWhereKey struct {
+	ID   int64
+	Name string `spanner:"UserName"`
+}
+
+func TestInWithStructs(t *testing.T) {
+	testWhere(t,
+		memeduck.In(memeduck.Ident("hoge"), memeduck.Unnest([]testWhereKey{{1, "foo"}, {2, "bar"}})),
+		`hoge IN UNNEST(ARRAY[STRUCT<ID INT64, UserName STRING>(1, "foo"), STRUCT<ID INT64, UserName STRING>(2, "bar")])`,
+	)
+}
+
 func TestNotIn(t *testing.T) {
 	testWhere(t, memeduck.NotIn(memeduck.Ident("hoge"), memeduck.Unnest(memeduck.Param("hoge"))), `hoge NOT IN UNNEST(@hoge)`)
 	testWhere(t, memeduck.NotIn(memeduck.Ident("hoge"), memeduck.Unnest([]string{"foo", "bar"})), `hoge NOT IN UNNEST(ARRAY["foo", "bar"])`)
@@ -89,8 +102,7 @@ func TestBetweenAndNotBetween(t *testing.T) {
 }
 
 func TestAnd(t *testing.T) {
-	_, err := memeduck.And().ToASTWhere()
-	assert.Error(t, err, "empty AND")
+	testWhere(t, memeduck.And(), `TRUE`)
 	testWhere(t,
 		memeduck.And(
 			memeduck.Op(1, memeduck.EQ, 1),
@@ -115,8 +127,7 @@ func TestAnd(t *testing.T) {
 }
 
 func TestOr(t *testing.T) {
-	_, err := memeduck.Or().ToASTWhere()
-	assert.Error(t, err, "empty Or")
+	testWhere(t, memeduck.Or(), `FALSE`)
 	testWhere(t,
 		memeduck.Or(
 			memeduck.Op(1, memeduck.EQ, 1),
@@ -139,15 +150,208 @@ func TestOr(t *testing.T) {
 		`1 = 1 OR "hoge" = "hoge" OR TRUE = TRUE`,
 	)
 
-	// TODO: this shoud pass
-	// testWhere(t,
-	// 	memeduck.And(
-	// 		memeduck.Eq(1, 1),
-	// 		memeduck.Or(
-	// 			memeduck.Eq(2, 2),
-	// 			memeduck.Eq(3, 3),
-	// 		),
-	// 	),
-	// 	`1 = 1 AND (2 = 2 OR 3 = 3)`,
-	// )
+	testWhere(t,
+		memeduck.And(
+			memeduck.Eq(1, 1),
+			memeduck.Or(
+				memeduck.Eq(2, 2),
+				memeduck.Eq(3, 3),
+			),
+		),
+		`1 = 1 AND (2 = 2 OR 3 = 3)`,
+	)
+	testWhere(t,
+		memeduck.Or(
+			memeduck.Eq(1, 1),
+			memeduck.And(
+				memeduck.Eq(2, 2),
+				memeduck.Eq(3, 3),
+			),
+		),
+		`1 = 1 OR 2 = 2 AND 3 = 3`,
+	)
+}
+
+func TestEmptyAndOr(t *testing.T) {
+	testWhere(t, memeduck.And(memeduck.And(), memeduck.And(memeduck.And())), `TRUE`)
+	testWhere(t, memeduck.Or(memeduck.Or(), memeduck.Or(memeduck.Or())), `FALSE`)
+	testWhere(t,
+		memeduck.And(
+			memeduck.And(),
+			memeduck.Eq(1, 1),
+			memeduck.And(),
+		),
+		`1 = 1`,
+	)
+	testWhere(t,
+		memeduck.Or(
+			memeduck.Or(),
+			memeduck.Eq(1, 1),
+			memeduck.Or(),
+		),
+		`1 = 1`,
+	)
+	testWhere(t, memeduck.And(memeduck.Eq(1, 1), memeduck.Or()), `1 = 1 AND FALSE`)
+	testWhere(t, memeduck.Or(memeduck.Eq(1, 1), memeduck.And()), `1 = 1 OR TRUE`)
+}
+
+func TestOpIfNotNil(t *testing.T) {
+	var nilStr *string
+	str := "hoge"
+	testWhere(t, memeduck.And(memeduck.OpIfNotNil(memeduck.Ident("a"), memeduck.GT, 1)), `a > 1`)
+	testWhere(t, memeduck.And(memeduck.OpIfNotNil(memeduck.Ident("a"), memeduck.GT, nil)), `TRUE`)
+	testWhere(t, memeduck.And(memeduck.EqIfNotNil(memeduck.Ident("a"), &str)), `a = "hoge"`)
+	testWhere(t, memeduck.And(memeduck.EqIfNotNil(memeduck.Ident("a"), nilStr)), `TRUE`)
+	testWhere(t, memeduck.And(memeduck.EqIfNotNil(memeduck.Ident("a"), spanner.NullInt64{})), `TRUE`)
+	testWhere(t, memeduck.And(memeduck.EqIfNotNil(memeduck.Ident("a"), spanner.NullInt64{Int64: 1, Valid: true})), `a = 1`)
+	assert.Nil(t, memeduck.EqIfNotNil(memeduck.Ident("a"), nil))
+}
+
+func TestWhen(t *testing.T) {
+	testWhere(t,
+		memeduck.And(
+			memeduck.When(true, memeduck.Eq(memeduck.Ident("a"), 1)),
+			memeduck.When(false, memeduck.Eq(memeduck.Ident("b"), 2)),
+		),
+		`a = 1`,
+	)
+	testWhere(t,
+		memeduck.Or(
+			memeduck.When(false, memeduck.Eq(memeduck.Ident("a"), 1)),
+			memeduck.When(false, memeduck.Eq(memeduck.Ident("b"), 2)),
+		),
+		`TRUE`,
+	)
+	assert.Nil(t, memeduck.When(false, memeduck.Bool(true)))
+}
+
+func TestNilConds(t *testing.T) {
+	var nilOp *memeduck.OpCond
+	testWhere(t, memeduck.And(nil, memeduck.Eq(1, 1), nil), `1 = 1`)
+	testWhere(t, memeduck.Or(nil, memeduck.Eq(1, 1), nilOp), `1 = 1`)
+	testWhere(t, memeduck.And(nil, nilOp), `TRUE`)
+	testWhere(t, memeduck.Or(nil, nilOp), `TRUE`)
+	testWhere(t, memeduck.Or(memeduck.Or(nil), memeduck.And(nil, memeduck.Or(nilOp))), `TRUE`)
+	testWhere(t,
+		memeduck.And(
+			memeduck.Eq(memeduck.Ident("s"), 1),
+			memeduck.Or(memeduck.EqIfNotNil(memeduck.Ident("a"), nil)),
+		),
+		`s = 1`,
+	)
+	testWhere(t,
+		memeduck.Or(
+			memeduck.Eq(memeduck.Ident("s"), 1),
+			memeduck.And(nil, nilOp),
+		),
+		`s = 1`,
+	)
+	testWhere(t, memeduck.And(memeduck.Eq(1, 1), memeduck.Or(nil, memeduck.Or())), `1 = 1 AND FALSE`)
+	testWhere(t,
+		memeduck.And(
+			memeduck.Eq(1, 1),
+			memeduck.Or(nil, memeduck.Eq(2, 2), nil, memeduck.Eq(3, 3)),
+		),
+		`1 = 1 AND (2 = 2 OR 3 = 3)`,
+	)
+}
+
+func TestEqMap(t *testing.T) {
+	testWhere(t, memeduck.EqMap(nil), `TRUE`)
+	testWhere(t, memeduck.EqMap(map[string]interface{}{"a": 1}), `a = 1`)
+	testWhere(t,
+		memeduck.EqMap(map[string]interface{}{
+			"c": "hoge",
+			"a": 1,
+			"b": true,
+		}),
+		`a = 1 AND b = TRUE AND c = "hoge"`,
+	)
+	testWhere(t,
+		memeduck.EqMap(map[string]interface{}{
+			"a": nil,
+			"b": (*string)(nil),
+			"c": spanner.NullInt64{},
+		}),
+		`a IS NULL AND b IS NULL AND c IS NULL`,
+	)
+	testWhere(t,
+		memeduck.EqMap(map[string]interface{}{
+			"a": []int64{1, 2},
+			"b": []byte("hoge"),
+		}),
+		`a IN UNNEST(ARRAY[1, 2]) AND b = B"hoge"`,
+	)
+	testWhere(t,
+		memeduck.Or(
+			memeduck.EqMap(map[string]interface{}{"a": 1, "b": 2}),
+			memeduck.EqMap(map[string]interface{}{"a": 3}),
+		),
+		`a = 1 AND b = 2 OR a = 3`,
+	)
+}
+
+type exampleUser struct {
+	ID      int64  `spanner:"user_id"`
+	Name    string `spanner:"name"`
+	Age     int64
+	Race    *string  `spanner:"race"`
+	Tags    []string `spanner:"tags"`
+	Icon    []byte   `spanner:"icon"`
+	Ignored string   `spanner:"-"`
+	private string
+}
+
+func TestEqStruct(t *testing.T) {
+	race := "Phoenix"
+	testWhere(t, memeduck.EqStruct(exampleUser{}), `TRUE`)
+	testWhere(t,
+		memeduck.EqStruct(exampleUser{ID: 1, Name: "hoge", Ignored: "a", private: "b"}),
+		`user_id = 1 AND name = "hoge"`,
+	)
+	testWhere(t,
+		memeduck.EqStruct(&exampleUser{Age: 12, Race: &race}),
+		`Age = 12 AND race = "Phoenix"`,
+	)
+	testWhere(t,
+		memeduck.EqStruct(exampleUser{Name: "hoge"}).IncludeZero("age", "race"),
+		`name = "hoge" AND Age = 0 AND race IS NULL`,
+	)
+	testWhere(t,
+		memeduck.EqStruct(exampleUser{Name: "hoge"}).IncludeZero("user_id").IncludeZero("name"),
+		`user_id = 0 AND name = "hoge"`,
+	)
+	testWhere(t,
+		memeduck.EqStruct(exampleUser{ID: 1, Icon: []byte("fuga")}),
+		`user_id = 1 AND icon = B"fuga"`,
+	)
+}
+
+func TestEqStructWithEmbeddedStruct(t *testing.T) {
+	testWhere(t,
+		memeduck.EqStruct(testInsertGoStructWithEmbedded{ID: 1}).IncludeZero("updated_by"),
+		`id = 1 AND updated_by IS NULL`,
+	)
+	testWhere(t,
+		memeduck.EqStruct(testInsertGoStructWithEmbedded{testAuditFields: &testAuditFields{UpdatedBy: "foo"}}),
+		`updated_by = "foo"`,
+	)
+}
+
+func TestEqStructWithInvalidArgs(t *testing.T) {
+	_, err := memeduck.EqStruct(1).ToASTWhere()
+	assert.Error(t, err, "not a struct")
+	_, err = memeduck.EqStruct((*exampleUser)(nil)).ToASTWhere()
+	assert.Error(t, err, "nil pointer")
+	_, err = memeduck.EqStruct(exampleUser{}).IncludeZero("no_such_column").ToASTWhere()
+	assert.Error(t, err, "unknown column")
+	_, err = memeduck.EqStruct(exampleUser{ID: 1, Tags: []string{"x"}}).ToASTWhere()
+	assert.Error(t, err, "slice field")
+	_, err = memeduck.EqStruct(exampleUser{ID: 1, Tags: []string{}}).ToASTWhere()
+	assert.Error(t, err, "empty slice field")
+	_, err = memeduck.EqStruct(exampleUser{ID: 1}).IncludeZero("tags").ToASTWhere()
+	assert.Error(t, err, "nil slice field")
+	_, err = memeduck.Delete("hoge").Where(memeduck.EqStruct(1)).SQL()
+	assert.Error(t, err, "DELETE with invalid struct")
+	assert.NotErrorIs(t, err, memeduck.ErrNoWhereClause)
 }